@@ -0,0 +1,89 @@
+package animagi
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+AmbiguityError is reported under WithStrictMatching for a field
+several source paths match equally well, the winner first
+*/
+type AmbiguityError struct {
+	Paths []string
+}
+
+func (e *AmbiguityError) Error() string {
+	return "ambiguous source, competing paths: " + strings.Join(e.Paths, ", ")
+}
+
+/*
+WithStrictMatching reports an AmbiguityError, instead of mapping the
+field, when the matcher finds several source paths with the
+best rank that are not an exact match.
+Without it the tie goes to the path as deep as the destination,
+then to the one declared first.
+*/
+func WithStrictMatching() Option {
+	return func(o *options) {
+		o.strictMatching = true
+	}
+}
+
+/*
+breakTie asks the matcher again, without the paths it already chose,
+for other sources with the rank of match and, when there are any,
+picks the winner deterministically and lists them all
+*/
+func (o *options) breakTie(names []string, match sourceMatch, srcDescription map[string]typeDescription) sourceMatch {
+	for _, name := range names {
+		if match.Path == name {
+			return match
+		}
+	}
+
+	type competitor struct {
+		path       string
+		wrongDepth bool
+		order      int
+	}
+	var competing []competitor
+	remaining := o.sources
+	for path := match.Path; ; {
+		i := sort.SearchStrings(remaining, path)
+		if i == len(remaining) || remaining[i] != path {
+			break
+		}
+		remaining = append(append(make([]string, 0, len(remaining)-1), remaining[:i]...), remaining[i+1:]...)
+
+		wrongDepth := true
+		for _, name := range names {
+			if strings.Count(name, ".") == strings.Count(path, ".") {
+				wrongDepth = false
+			}
+		}
+		competing = append(competing, competitor{path, wrongDepth, srcDescription[path].Order})
+
+		next, rank, found := o.matcher(names, remaining, o.similarity.Rank)
+		if !found || rank != match.Rank {
+			break
+		}
+		path = next
+	}
+	if len(competing) < 2 {
+		return match
+	}
+
+	sort.SliceStable(competing, func(i, j int) bool {
+		if competing[i].wrongDepth != competing[j].wrongDepth {
+			return !competing[i].wrongDepth
+		}
+		return competing[i].order < competing[j].order
+	})
+	match.Path = competing[0].path
+	match.Competing = make([]string, len(competing))
+	for i, c := range competing {
+		match.Competing[i] = c.path
+	}
+	return match
+}
@@ -0,0 +1,105 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type TiedNames struct {
+	Nome string
+	Nane string
+}
+
+type TiedDepths struct {
+	Home struct{ City string }
+	Cxxx string
+}
+
+type TiedCases struct {
+	NAME string
+	NaMe string
+}
+
+type TiedWords struct {
+	Addr string
+	ADDR string
+}
+
+var _ = Describe("Ambiguous matches", func() {
+
+	fuzzy := animagi.WithMatcher(animagi.FuzzyMatch(5))
+
+	It("Should give ties to the path declared first", func() {
+		for i := 0; i < 20; i++ {
+			var dst struct{ Name string }
+			err := animagi.Transform(TiedNames{"declared first", "declared second"}, &dst, fuzzy)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Name).To(Equal("declared first"))
+		}
+	})
+
+	It("Should give ties to the path as deep as the destination", func() {
+		src := TiedDepths{Cxxx: "same depth"}
+		src.Home.City = "deeper"
+		var dst struct{ City string }
+		err := animagi.Transform(src, &dst, fuzzy)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.City).To(Equal("same depth"))
+	})
+
+	It("Should report ties as errors when strict", func() {
+		dst := struct{ Name string }{"kept"}
+		err := animagi.Transform(TiedNames{"first", "second"}, &dst, fuzzy, animagi.WithStrictMatching())
+		Expect(err).To(HaveOccurred())
+		errs := err.(animagi.FieldErrors)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Path).To(Equal("Name"))
+		Expect(errs[0].Err).To(Equal(&animagi.AmbiguityError{Paths: []string{"Nome", "Nane"}}))
+		Expect(dst.Name).To(Equal("kept"))
+	})
+
+	It("Should not report exact matches", func() {
+		var dst struct{ Nome string }
+		err := animagi.Transform(TiedNames{"first", "second"}, &dst, fuzzy, animagi.WithStrictMatching())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Nome).To(Equal("first"))
+	})
+
+	It("Should report ties of names equal but for case", func() {
+		folding := animagi.DefaultSimilarityOptions
+		folding.FoldCase = true
+		var dst struct{ Name string }
+		err := animagi.Transform(TiedCases{"first", "second"}, &dst, fuzzy, animagi.WithSimilarity(folding), animagi.WithStrictMatching())
+		Expect(err).To(Equal(animagi.FieldErrors{{Path: "Name", Err: &animagi.AmbiguityError{Paths: []string{"NAME", "NaMe"}}}}))
+
+		err = animagi.Transform(TiedCases{"first", "second"}, &dst, fuzzy, animagi.WithSimilarity(folding))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Name).To(Equal("first"))
+	})
+
+	It("Should report ties of abbreviated words", func() {
+		tokens := animagi.DefaultSimilarityOptions
+		tokens.Tokenize = true
+		tokens.Dictionary = animagi.DefaultDictionary()
+		var dst struct{ Address string }
+		err := animagi.Transform(TiedWords{"first", "second"}, &dst, fuzzy, animagi.WithSimilarity(tokens), animagi.WithStrictMatching())
+		Expect(err).To(Equal(animagi.FieldErrors{{Path: "Address", Err: &animagi.AmbiguityError{Paths: []string{"Addr", "ADDR"}}}}))
+	})
+
+	It("Should only break ties among the paths the matcher reports", func() {
+		onlyNane := func(names []string, sources []string, rank func(string, string) uint) (string, uint, bool) {
+			for _, source := range sources {
+				if source == "Nane" {
+					return source, rank(names[0], source), true
+				}
+			}
+			return "", animagi.MaxRank, false
+		}
+		var dst struct{ Name string }
+		err := animagi.Transform(TiedNames{"first", "second"}, &dst, animagi.WithMatcher(onlyNane), animagi.WithStrictMatching())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Name).To(Equal("second"))
+	})
+})
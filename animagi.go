@@ -3,16 +3,24 @@ package animagi
 import (
 	"errors"
 	"reflect"
+	"strings"
 )
 
 const (
 	dstError                  = "dst must be settable"
 	unsupportedTransformation = "could not transform to dst"
+	// tagName renames a field for matching, or ignores it when set to "-"
+	tagName = "animagi"
 )
 
+/*
+typeDescription is a source leaf; Order is its position
+in declaration order, used to break ties between matches
+*/
 type typeDescription struct {
 	FieldType  reflect.Type
 	FieldValue reflect.Value
+	Order      int
 }
 
 /*
@@ -20,11 +28,22 @@ Transform will map the data from src into
 dst by calculating the fields most similar
 counterpart and copying the values over.
 If src and dst are of the same type then
-Transform basically does a copy.
+Transform basically does a copy, sharing slices,
+maps and pointers unless WithDeepCopy is given.
 
-dst must be settable or an error will be returned
+dst must be settable or an error will be returned.
+Fields that fail to convert are left untouched and
+reported together as FieldErrors.
 */
-func Transform(src, dst interface{}) (err error) {
+func Transform(src, dst interface{}, opts ...Option) error {
+	return defaultMapper.Transform(src, dst, opts...)
+}
+
+/*
+Transform maps src into dst with the rules of the Mapper,
+opts apply to this call only
+*/
+func (m *Mapper) Transform(src, dst interface{}, opts ...Option) error {
 
 	if cannotModifyField(dst) {
 		return errors.New(dstError)
@@ -32,79 +51,363 @@ func Transform(src, dst interface{}) (err error) {
 
 	valueOfSrc := findValueOf(src)
 	valueOfDst := findValueOf(dst)
+	if !valueOfSrc.IsValid() || valueOfSrc.Kind() != valueOfDst.Kind() {
+		return errors.New(unsupportedTransformation)
+	}
+	options := m.callOptions(valueOfSrc.Type(), valueOfDst.Type(), opts)
+	options.src = src
 
-	if valueOfSrc.Kind() == valueOfDst.Kind() {
-		switch valueOfDst.Kind() {
-		case reflect.Struct:
-			srcDescription := describeStructure(src)
-			mapToDestination("", dst, srcDescription)
-		default:
-			setValueOfDst(valueOfDst, valueOfSrc)
+	var errs FieldErrors
+	switch valueOfDst.Kind() {
+	case reflect.Struct:
+		srcDescription := describeStructure(src)
+		mapToRoot(dst, srcDescription, options, &errs)
+	default:
+		if !skipAssignment(valueOfDst, valueOfSrc, options) {
+			old := options.changes.snapshot(valueOfDst)
+			if err := setValueOfDst(valueOfDst, valueOfSrc, options); err != nil {
+				errs.add("", err)
+			} else {
+				options.changes.record(0, rootPaths[0], old, valueOfDst, "")
+			}
 		}
-	} else {
-		err = errors.New(unsupportedTransformation)
 	}
+	return errs.orNil()
+}
+
+/*
+fieldPath is one of the names a struct can be reached by.
+When owner is set the struct is embedded and its fields are
+promoted into prefix, unless owner shadows them the way Go would.
+field is the index of the struct from the root, through pointers.
+*/
+type fieldPath struct {
+	prefix string
+	owner  reflect.Type
+	index  []int
+	field  []int
+}
+
+var rootPaths = []fieldPath{{}}
+
+/*
+pathsOfField lists every name the i-th field of structType is
+reachable by: first the qualified names and then, for embedded
+fields, the paths its own fields get promoted into.
+*/
+func pathsOfField(paths []fieldPath, structType reflect.Type, i int) []fieldPath {
+	field := structType.Field(i)
+	name := fieldName(field)
+	if name == "-" {
+		return nil
+	}
+	var named, promoted []fieldPath
 
-	return err
+	for _, path := range paths {
+		index := append(append([]int{}, path.index...), i)
+		location := append(append([]int{}, path.field...), i)
+		if path.owner != nil {
+			if promotedField, ok := path.owner.FieldByName(field.Name); !ok || !reflect.DeepEqual(promotedField.Index, index) {
+				continue
+			}
+		}
+		named = append(named, fieldPath{prefix: appendFieldName(path.prefix, name), field: location})
+
+		if field.Anonymous {
+			if path.owner == nil {
+				promoted = append(promoted, fieldPath{path.prefix, structType, []int{i}, location})
+			} else {
+				promoted = append(promoted, fieldPath{path.prefix, path.owner, index, location})
+			}
+		}
+	}
+	return append(named, promoted...)
+}
+
+func fieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get(tagName); len(tag) != 0 {
+		return tag
+	}
+	return field.Name
+}
+
+/*
+describedLeaf is a leaf field of a structure
+along with every path it can be reached by.
+A part is a field of a value struct, described
+as well as the value struct itself.
+*/
+type describedLeaf struct {
+	names []string
+	part  bool
+	typeDescription
 }
 
 func describeStructure(structure interface{}) map[string]typeDescription {
 	structureDescription := make(map[string]typeDescription)
-	structureValue := findValueOf(structure)
+	for _, leaf := range describeLeaves(structure) {
+		for _, name := range leaf.names {
+			structureDescription[name] = leaf.typeDescription
+		}
+	}
+	return structureDescription
+}
+
+func describeLeaves(structure interface{}) (leaves []describedLeaf) {
+	describeFields(rootPaths, findValueOf(structure), false, &leaves)
+	return leaves
+}
 
+func describeFields(paths []fieldPath, structureValue reflect.Value, part bool, leaves *[]describedLeaf) {
 	for i := 0; i < structureValue.NumField(); i++ {
 		field := structureValue.Field(i)
-		fieldName := structureValue.Type().Field(i).Name
-		switch reflect.Indirect(field).Kind() {
-		case reflect.Struct:
-			subDescription := describeStructure(field)
-			for k, v := range subDescription {
-				structureDescription[fieldName+"."+k] = v
-			}
+		fieldPaths := pathsOfField(paths, structureValue.Type(), i)
+		if len(fieldPaths) == 0 {
+			continue
+		}
+		value := reflect.Indirect(field)
+		switch {
+		case value.Kind() == reflect.Struct && isValueStruct(value.Type()):
+			describeLeaf(fieldPaths, field, part, leaves)
+			describeFields(fieldPaths, value, true, leaves)
+		case value.Kind() == reflect.Struct && !isAtomic(value.Type()):
+			describeFields(fieldPaths, value, part, leaves)
 		default:
-			structureDescription[fieldName] = typeDescription{field.Type(), findValueOf(field)}
+			describeLeaf(fieldPaths, field, part, leaves)
 		}
 	}
-	return structureDescription
 }
 
-func mapToDestination(currentLevel string, dst interface{}, srcDescription map[string]typeDescription) {
+/*
+describeLeaf adds field to leaves unless it is unexported, so
+neither a tag nor a fuzzy match can select what cannot be read
+*/
+func describeLeaf(fieldPaths []fieldPath, field reflect.Value, part bool, leaves *[]describedLeaf) {
+	if field.CanInterface() {
+		*leaves = append(*leaves, describedLeaf{namesOf(fieldPaths), part, typeDescription{field.Type(), findValueOf(field), len(*leaves)}})
+	}
+}
+
+/*
+namesOf returns the paths a field can be looked up by,
+leaving out the promotion targets used only while walking
+*/
+func namesOf(fieldPaths []fieldPath) (names []string) {
+	for _, path := range fieldPaths {
+		if path.owner == nil {
+			names = append(names, path.prefix)
+		}
+	}
+	return names
+}
+
+/*
+mapToRoot maps onto the top level dst struct and validates it
+*/
+func mapToRoot(dst interface{}, srcDescription map[string]typeDescription, options *options, errs *FieldErrors) {
+	mapToDestination(rootPaths, dst, srcDescription, options, errs)
+	options.validate("", findValueOf(dst), errs)
+}
+
+/*
+mapToDestination fills every settable field of dst from its
+counterpart in srcDescription and reports whether anything was set
+*/
+func mapToDestination(paths []fieldPath, dst interface{}, srcDescription map[string]typeDescription, options *options, errs *FieldErrors) (mapped bool) {
 	dstValue := findValueOf(dst)
 
 	for i := 0; i < dstValue.NumField(); i++ {
 		field := dstValue.Field(i)
-		fieldName := dstValue.Type().Field(i).Name
-		fullPathName := appendFieldName(currentLevel, fieldName)
+		fieldPaths := pathsOfField(paths, dstValue.Type(), i)
+		if len(fieldPaths) == 0 {
+			continue
+		}
+		fullPathName := fieldPaths[0].prefix
+
+		if embedded := reflect.Indirect(field); !field.CanSet() && dstValue.Type().Field(i).Anonymous &&
+			embedded.Kind() == reflect.Struct && !isAtomic(embedded.Type()) {
+			// unexported embedded structs cannot be set but their promoted fields can
+			if mapToDestination(fieldPaths, embedded, srcDescription, options, errs) {
+				mapped = true
+			}
+			continue
+		}
 
 		if field.IsValid() && field.CanSet() {
-			switch field.Kind() {
-			case reflect.Struct:
-				mapToDestination(fullPathName, field, srcDescription)
-			case reflect.Ptr:
-				if val, found := findMostSimlilarSource(fullPathName, srcDescription); found {
-					field.Set(reflect.New(reflect.TypeOf(field.Interface()).Elem()))
-					setValueOfDst(field.Elem(), val.FieldValue)
+			names := namesOf(fieldPaths)
+			rule, ruled := options.memberRule(names)
+			if rule.ignore {
+				continue
+			}
+
+			val, found, sourcePaths := sourceMatch{}, false, fieldPaths
+			if ruled {
+				var err error
+				if val, found, err = options.ruledSource(rule, srcDescription); err != nil {
+					errs.add(fullPathName, err)
+					continue
+				}
+				if len(rule.from) != 0 {
+					sourcePaths = append(fieldPaths[:len(fieldPaths):len(fieldPaths)], fieldPath{prefix: rule.from})
+				}
+			} else {
+				val, found = options.findSource(names, srcDescription)
+				if !found && indirectType(field.Type()) == timeType {
+					val, found = options.timestampSource(names, srcDescription)
+				}
+			}
+
+			if found && skipAssignment(field, val.FieldValue, options) {
+				continue
+			}
+			old := options.changes.snapshot(field)
+			switch {
+			case field.Kind() == reflect.Struct && !isAtomic(field.Type()) && !isTimeOnto(field.Type(), val, found) &&
+				!(isValueStruct(field.Type()) && (found && isSingleValue(val.FieldType) || !hasSourcesBelow(sourcePaths, srcDescription))):
+				if mapToDestination(fieldPaths, field, srcDescription, options, errs) {
+					options.validate(fullPathName, field, errs)
+					mapped = true
+				}
+			case field.Kind() == reflect.Ptr && (!found || isValueSource(val) && !isValueStruct(field.Type().Elem())) &&
+				isComposite(field.Type()) && hasSourcesBelow(sourcePaths, srcDescription):
+				ptr := reflect.New(field.Type().Elem())
+				if !field.IsNil() {
+					ptr.Elem().Set(field.Elem())
+				}
+				at := options.changes.len()
+				if mapToDestination(fieldPaths, ptr.Elem(), srcDescription, options, errs) {
+					field.Set(ptr)
+					options.changes.record(at, fieldPaths[0], old, field, "")
+					options.validate(fullPathName, ptr, errs)
+					mapped = true
+				}
+			case !found:
+				if options.strict {
+					errs.add(fullPathName, ErrUnmapped)
 				}
 			default:
-				if val, found := findMostSimlilarSource(fullPathName, srcDescription); found {
-					setValueOfDst(field, val.FieldValue)
+				if len(val.Competing) != 0 && options.strictMatching {
+					errs.add(fullPathName, &AmbiguityError{val.Competing})
+					continue
+				}
+				context := FieldContext{fullPathName, val.Path, val.Rank}
+				if skip, err := options.beforeField(context, val.FieldValue, field); skip || err != nil {
+					errs.add(fullPathName, err)
+					continue
+				}
+				if err := assignField(field, val.FieldValue, options); err != nil {
+					errs.add(fullPathName, err)
+					continue
 				}
+				options.afterField(context, field)
+				options.reportConflict(fullPathName, val.Path, errs)
+				options.changes.record(options.changes.len(), fieldPaths[0], old, field, val.Path)
+				mapped = true
 			}
 		}
 	}
+	return mapped
 }
 
-func findMostSimlilarSource(fullPathName string, srcDescription map[string]typeDescription) (typeDescription, bool) {
-	val, ok := srcDescription[fullPathName]
-	return val, ok
+/*
+hasSourcesBelow tells if any source path could land inside the
+struct reached by fieldPaths, so pointers are only allocated
+(and recursive types only followed) when there is data for them
+*/
+func hasSourcesBelow(fieldPaths []fieldPath, srcDescription map[string]typeDescription) bool {
+	for key := range srcDescription {
+		for _, path := range fieldPaths {
+			if path.owner == nil && strings.HasPrefix(key, path.prefix+".") {
+				return true
+			}
+			if path.owner != nil && (len(path.prefix) == 0 || strings.HasPrefix(key, path.prefix+".")) {
+				relative := strings.TrimPrefix(strings.TrimPrefix(key, path.prefix), ".")
+				promotedField, ok := path.owner.FieldByName(strings.Split(relative, ".")[0])
+				if ok && len(promotedField.Index) > len(path.index) && reflect.DeepEqual(promotedField.Index[:len(path.index)], path.index) {
+					return true
+				}
+			}
+		}
+	}
+	return false
 }
 
-func setValueOfDst(dst, src reflect.Value) {
-	if dst.Type() == reflect.Indirect(src).Type() {
-		dst.Set(reflect.Indirect(src))
+/*
+sourceMatch is the source field picked for a destination,
+with the path it was found by and its SimilarityRank.
+Competing lists the paths that tied for it, if any.
+*/
+type sourceMatch struct {
+	Path      string
+	Rank      uint
+	Competing []string
+	typeDescription
+}
+
+/*
+assignField sets the leaf field dst from src, allocating
+new memory when dst is a pointer
+*/
+func assignField(dst, src reflect.Value, options *options) error {
+	src, err := options.unwrapSource(src, dst.Type())
+	if err != nil {
+		return err
+	}
+	if !reflect.Indirect(src).IsValid() && options.nilPolicy == NilError {
+		return ErrNilSource
+	}
+	if dst.Kind() != reflect.Ptr {
+		return setValueOfDst(dst, src, options)
+	}
+	if !reflect.Indirect(src).IsValid() {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	ptr := reflect.New(dst.Type().Elem())
+	if err := setValueOfDst(ptr.Elem(), src, options); err != nil {
+		return err
+	}
+	dst.Set(ptr)
+	return nil
+}
+
+func skipAssignment(dst, src reflect.Value, options *options) bool {
+	if options.skipZeroSource && (!src.IsValid() || src.IsZero()) {
+		return true
+	}
+	if options.nilPolicy == NilSkip && isNull(src) {
+		return true
+	}
+	return options.onlyZeroDst && !dst.IsZero()
+}
+
+func setValueOfDst(dst, src reflect.Value, options *options) error {
+	if !reflect.Indirect(src).IsValid() {
+		dst.Set(reflect.Zero(dst.Type()))
+	} else if converted, err := options.convert(dst, reflect.Indirect(src)); converted {
+		return err
+	} else if dst.Type() == reflect.Indirect(src).Type() {
+		dst.Set(options.copyOf(reflect.Indirect(src)))
+	} else if scanned, err := scanInto(dst, reflect.Indirect(src)); scanned {
+		return err
+	} else if converted, err := options.convertTime(dst, reflect.Indirect(src)); converted {
+		return err
+	} else if converted, err := options.convertEnum(dst, reflect.Indirect(src)); converted {
+		return err
+	} else if converted, err := options.convertText(dst, reflect.Indirect(src)); converted {
+		return err
+	} else if options.conversionPolicy != ConversionAllow && isNumber(reflect.Indirect(src).Kind()) && isNumber(dst.Kind()) {
+		converted, err := checkedConvert(reflect.Indirect(src), dst.Type(), options.conversionPolicy)
+		if err != nil {
+			return err
+		}
+		dst.Set(converted)
+	} else if !options.runeConversion && isIntegerToString(reflect.Indirect(src), dst.Type()) {
+		dst.SetString(formatInteger(reflect.Indirect(src)))
 	} else if reflect.Indirect(src).Type().ConvertibleTo(reflect.Indirect(dst).Type()) {
-		dst.Set(reflect.Indirect(src).Convert(reflect.Indirect(dst).Type()))
+		dst.Set(options.copyOf(reflect.Indirect(src).Convert(reflect.Indirect(dst).Type())))
 	}
+	return nil
 }
 
 func findValueOf(val interface{}) (valueOf reflect.Value) {
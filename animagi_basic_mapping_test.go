@@ -208,4 +208,39 @@ var _ = Describe("AnimagiBasicMapping", func() {
 
 		})
 	})
+
+	Context("Tagged fields", func() {
+		It("Should match fields by their animagi tag", func() {
+			src := struct {
+				Name   string
+				Nested struct{ Town string }
+				Hidden string
+			}{"tagged", struct{ Town string }{"Springfield"}, "hidden"}
+
+			var dst struct {
+				Label  string `animagi:"Name"`
+				City   string `animagi:"Nested.Town"`
+				Hidden string `animagi:"-"`
+			}
+
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Label).To(Equal("tagged"))
+			Expect(dst.City).To(Equal("Springfield"))
+			Expect(dst.Hidden).To(BeEmpty())
+		})
+
+		It("Should not select unexported source fields", func() {
+			src := struct{ secret string }{"s"}
+			var dst struct {
+				Secret string `animagi:"secret"`
+			}
+
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Secret).To(BeEmpty())
+			err = animagi.Transform(src, &struct{ Secrets string }{}, animagi.WithMatcher(animagi.FuzzyMatch(10)))
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,213 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Base struct {
+	ID   int
+	Name string
+}
+
+type Audit struct {
+	Name    string
+	Version int
+}
+
+type EmbeddedEntity struct {
+	Base
+	Title string
+}
+
+type ShadowingEntity struct {
+	Base
+	Audit
+	Name string
+}
+
+type AmbiguousEntity struct {
+	Base
+	Audit
+}
+
+type EmbeddedPointerEntity struct {
+	*Base
+	Title string
+}
+
+type base struct{ ID int }
+
+type UnexportedEmbedEntity struct {
+	base
+	Name string
+}
+
+type DeeplyEmbeddedEntity struct {
+	EmbeddedEntity
+}
+
+type Node struct {
+	Value int
+	Next  *Node
+}
+
+var _ = Describe("AnimagiEmbeddedStructs", func() {
+
+	Context("Promoted fields", func() {
+		It("Should map promoted source fields onto plain fields", func() {
+			src := EmbeddedEntity{Base{7, "base"}, "title"}
+			var dst struct {
+				ID    int
+				Name  string
+				Title string
+			}
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.ID).To(Equal(7))
+			Expect(dst.Name).To(Equal("base"))
+			Expect(dst.Title).To(Equal("title"))
+		})
+
+		It("Should still map by the qualified name", func() {
+			src := EmbeddedEntity{Base{7, "base"}, "title"}
+			var dst struct {
+				Base struct{ ID int }
+			}
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Base.ID).To(Equal(7))
+		})
+
+		It("Should map plain fields onto promoted destination fields", func() {
+			src := struct {
+				ID    int
+				Title string
+			}{9, "title"}
+			var dst EmbeddedEntity
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.ID).To(Equal(9))
+			Expect(dst.Title).To(Equal("title"))
+		})
+
+		It("Should promote through several levels", func() {
+			src := DeeplyEmbeddedEntity{EmbeddedEntity{Base{3, "deep"}, "title"}}
+			var dst struct {
+				ID   int
+				Base Base
+			}
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.ID).To(Equal(3))
+			Expect(dst.Base.Name).To(Equal("deep"))
+		})
+	})
+
+	Context("Unexported embedded structs", func() {
+		It("Should fill their promoted fields", func() {
+			var dst UnexportedEmbedEntity
+			err := animagi.Transform(struct {
+				ID   int
+				Name string
+			}{7, "n"}, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.ID).To(Equal(7))
+			Expect(dst.Name).To(Equal("n"))
+		})
+
+		It("Should copy them between values of the same type", func() {
+			src := UnexportedEmbedEntity{Name: "n"}
+			src.ID = 7
+			var dst UnexportedEmbedEntity
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst).To(Equal(src))
+		})
+	})
+
+	Context("Shadowing", func() {
+		It("Should prefer the shallowest field", func() {
+			src := ShadowingEntity{Base{1, "base"}, Audit{"audit", 2}, "outer"}
+			var dst struct {
+				ID      int
+				Name    string
+				Version int
+			}
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Name).To(Equal("outer"))
+			Expect(dst.ID).To(Equal(1))
+			Expect(dst.Version).To(Equal(2))
+		})
+
+		It("Should not promote ambiguous fields", func() {
+			src := AmbiguousEntity{Base{1, "base"}, Audit{"audit", 2}}
+			var dst struct {
+				ID    int
+				Name  string
+				Audit struct{ Name string }
+			}
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.ID).To(Equal(1))
+			Expect(dst.Name).To(BeEmpty())
+			Expect(dst.Audit.Name).To(Equal("audit"))
+		})
+
+		It("Should not fill shadowed destination fields from promoted names", func() {
+			src := struct{ Name string }{"plain"}
+			var dst ShadowingEntity
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Name).To(Equal("plain"))
+			Expect(dst.Base.Name).To(BeEmpty())
+			Expect(dst.Audit.Name).To(BeEmpty())
+		})
+	})
+
+	Context("Embedded pointers", func() {
+		It("Should promote fields of embedded pointers", func() {
+			src := EmbeddedPointerEntity{&Base{5, "pointer"}, "title"}
+			var dst struct {
+				ID   int
+				Name string
+			}
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.ID).To(Equal(5))
+			Expect(dst.Name).To(Equal("pointer"))
+		})
+
+		It("Should allocate embedded pointers in the destination", func() {
+			src := struct{ ID int }{11}
+			var dst EmbeddedPointerEntity
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Base).NotTo(BeNil())
+			Expect(dst.ID).To(Equal(11))
+		})
+
+		It("Should leave embedded pointers nil when nothing maps into them", func() {
+			src := struct{ Title string }{"title"}
+			var dst EmbeddedPointerEntity
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Base).To(BeNil())
+			Expect(dst.Title).To(Equal("title"))
+		})
+
+		It("Should follow recursive pointers only as deep as the source", func() {
+			src := Node{1, &Node{2, nil}}
+			var dst Node
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Value).To(Equal(1))
+			Expect(dst.Next.Value).To(Equal(2))
+			Expect(dst.Next.Next).To(BeNil())
+			Expect(dst.Next).NotTo(BeIdenticalTo(src.Next))
+		})
+	})
+})
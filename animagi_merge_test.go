@@ -0,0 +1,92 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type Customer struct {
+	Name    string
+	Age     int
+	Email   *string
+	Address Address
+}
+
+type CustomerPatch struct {
+	Name    string
+	Age     *int
+	Email   *string
+	Address Address
+}
+
+var _ = Describe("AnimagiMerge", func() {
+
+	var entity Customer
+
+	BeforeEach(func() {
+		email := "old@example.com"
+		entity = Customer{"Jane", 40, &email, Address{"Main St", "Springfield"}}
+	})
+
+	Context("Skipping zero source fields", func() {
+		It("Should overwrite everything by default", func() {
+			patch := CustomerPatch{Name: "Janet"}
+			err := animagi.Transform(patch, &entity)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entity.Name).To(Equal("Janet"))
+			Expect(entity.Email).To(BeNil())
+			Expect(entity.Address.City).To(BeEmpty())
+		})
+
+		It("Should only copy fields that are set", func() {
+			patch := CustomerPatch{Name: "Janet", Address: Address{City: "Shelbyville"}}
+			err := animagi.Transform(patch, &entity, animagi.WithSkipZeroSource())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entity.Name).To(Equal("Janet"))
+			Expect(entity.Age).To(Equal(40))
+			Expect(*entity.Email).To(Equal("old@example.com"))
+			Expect(entity.Address.Street).To(Equal("Main St"))
+			Expect(entity.Address.City).To(Equal("Shelbyville"))
+		})
+
+		It("Should copy pointers to zero values", func() {
+			zero := 0
+			patch := CustomerPatch{Age: &zero}
+			err := animagi.Transform(patch, &entity, animagi.WithSkipZeroSource())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entity.Age).To(BeZero())
+			Expect(entity.Name).To(Equal("Jane"))
+		})
+	})
+
+	Context("Overwriting only zero destination fields", func() {
+		It("Should keep filled destination fields", func() {
+			entity.Name = ""
+			entity.Address.Street = ""
+			defaults := CustomerPatch{Name: "Default", Address: Address{"Default St", "Default City"}}
+			err := animagi.Transform(defaults, &entity, animagi.WithOverwriteOnlyZero())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entity.Name).To(Equal("Default"))
+			Expect(entity.Address.Street).To(Equal("Default St"))
+			Expect(entity.Address.City).To(Equal("Springfield"))
+			Expect(*entity.Email).To(Equal("old@example.com"))
+		})
+
+		It("Should combine with skipping zero source fields", func() {
+			entity.Age = 0
+			defaults := CustomerPatch{Name: "Default"}
+			err := animagi.Transform(defaults, &entity, animagi.WithOverwriteOnlyZero(), animagi.WithSkipZeroSource())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entity.Name).To(Equal("Jane"))
+			Expect(entity.Age).To(BeZero())
+			Expect(entity.Address.City).To(Equal("Springfield"))
+		})
+	})
+})
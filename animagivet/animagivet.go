@@ -0,0 +1,457 @@
+/*
+Package animagivet defines an Analyzer that checks calls to
+animagi's Transform whose arguments have statically known types.
+
+It reports destinations that are not pointers and so cannot be set,
+destination fields that no source field maps onto, source fields that
+are dropped and numeric conversions that may lose data.  Fields are
+flattened and matched with the rules Transform uses, so the reports
+hold for the default Mapper.  Calls on a Mapper and calls given
+options are not checked, as their rules, such as a matcher or
+ForMember, are only known at runtime.
+*/
+package animagivet
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/barreeyentos/animagi"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	animagiPath = "github.com/barreeyentos/animagi"
+	tagName     = "animagi"
+)
+
+/*
+Analyzer checks calls to Transform
+*/
+var Analyzer = &analysis.Analyzer{
+	Name:     "animagi",
+	Doc:      "check animagi.Transform calls for unmapped fields, unsettable destinations and lossy conversions",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// maxRank checks calls as mapped with animagi.FuzzyMatch(maxRank) when set
+var maxRank uint
+
+func init() {
+	Analyzer.Flags.UintVar(&maxRank, "rank", 0, "check fields as matched by animagi.FuzzyMatch with this rank, 0 for exact matching")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != animagiPath || fn.Name() != "Transform" {
+			return
+		}
+		// a Mapper or options may change the rules fields are matched with
+		if fn.Type().(*types.Signature).Recv() != nil || len(call.Args) != 2 || call.Ellipsis.IsValid() {
+			return
+		}
+		checkTransform(pass, call)
+	})
+	return nil, nil
+}
+
+func checkTransform(pass *analysis.Pass, call *ast.CallExpr) {
+	srcType := pass.TypesInfo.TypeOf(call.Args[0])
+	dstType := pass.TypesInfo.TypeOf(call.Args[1])
+	if srcType == nil || dstType == nil || types.IsInterface(srcType) || types.IsInterface(dstType) {
+		return
+	}
+
+	dstPointer, ok := dstType.Underlying().(*types.Pointer)
+	if !ok {
+		pass.Reportf(call.Args[1].Pos(), "Transform cannot set dst of type %s, pass a pointer", dstType)
+		return
+	}
+
+	srcStruct, srcIsStruct := deref(srcType).Underlying().(*types.Struct)
+	dstStruct, dstIsStruct := dstPointer.Elem().Underlying().(*types.Struct)
+	if srcIsStruct != dstIsStruct {
+		pass.Reportf(call.Pos(), "Transform cannot map %s onto %s", srcType, dstPointer.Elem())
+		return
+	}
+	if !srcIsStruct {
+		if lossy(pass, deref(srcType), dstPointer.Elem()) {
+			pass.Reportf(call.Pos(), "converting %s to %s may lose data", deref(srcType), dstPointer.Elem())
+		}
+		return
+	}
+
+	srcLeaves := leavesOf(srcStruct)
+	dstLeaves := leavesOf(dstStruct)
+
+	leafOf := make(map[string]int)
+	var sources []string
+	for i, leaf := range srcLeaves {
+		if len(leaf.of) != 0 {
+			i = leafOf[leaf.of]
+		}
+		for _, name := range leaf.names {
+			leafOf[name] = i
+			sources = append(sources, name)
+		}
+	}
+	sort.Strings(sources)
+
+	matcher := animagi.ExactMatch
+	if maxRank != 0 {
+		matcher = animagi.FuzzyMatch(maxRank)
+	}
+
+	used := make(map[int]bool)
+	whole := make(map[string]bool)
+	var unmapped, dropped []string
+	for _, leaf := range dstLeaves {
+		if whole[leaf.of] {
+			continue
+		}
+		source, _, found := matcher(leaf.names, sources, animagi.SimilarityRank)
+		if isValueStruct(deref(leaf.typ)) {
+			if whole[leaf.names[0]] = found && isSingleValue(srcLeaves[leafOf[source]].typ); !whole[leaf.names[0]] {
+				continue
+			}
+		}
+		if !found && isTime(leaf.typ) {
+			if timestamp, fromTimestamp := timestampSource(matcher, leaf.names, sources, leafOf); fromTimestamp {
+				used[leafOf[timestamp+".Seconds"]], used[leafOf[timestamp+".Nanos"]] = true, true
+				continue
+			}
+		}
+		if !found && isTimestamp(leaf.parent) {
+			parents := make([]string, len(leaf.names))
+			for i, name := range leaf.names {
+				parents[i] = parentPath(name)
+			}
+			if parent, _, fromTime := matcher(parents, sources, animagi.SimilarityRank); fromTime && isTime(srcLeaves[leafOf[parent]].typ) {
+				used[leafOf[parent]] = true
+				continue
+			}
+		}
+		if !found {
+			unmapped = append(unmapped, leaf.names[0])
+			continue
+		}
+		srcLeaf := srcLeaves[leafOf[source]]
+		used[leafOf[source]] = true
+		if lossy(pass, srcLeaf.typ, leaf.typ) {
+			pass.Reportf(call.Pos(), "%s: converting %s to %s may lose data", leaf.names[0], srcLeaf.typ, leaf.typ)
+		}
+	}
+	for i, leaf := range srcLeaves {
+		if !used[i] && len(leaf.of) == 0 {
+			dropped = append(dropped, leaf.names[0])
+		}
+	}
+
+	if len(unmapped) != 0 {
+		pass.Reportf(call.Pos(), "Transform leaves %s of %s unmapped", strings.Join(unmapped, ", "), dstPointer.Elem())
+	}
+	if len(dropped) != 0 {
+		pass.Reportf(call.Pos(), "Transform drops %s of %s", strings.Join(dropped, ", "), deref(srcType))
+	}
+}
+
+/*
+timestampSource finds the Seconds and Nanos fields a time.Time
+destination reached by names is read from, as Transform does
+*/
+func timestampSource(matcher animagi.MatchFunc, names, sources []string, leafOf map[string]int) (string, bool) {
+	secondsNames := make([]string, len(names))
+	for i, name := range names {
+		secondsNames[i] = appendFieldName(name, "Seconds")
+	}
+	seconds, _, found := matcher(secondsNames, sources, animagi.SimilarityRank)
+	if !found || !strings.HasSuffix(seconds, ".Seconds") {
+		return "", false
+	}
+	path := strings.TrimSuffix(seconds, ".Seconds")
+	_, hasNanos := leafOf[path+".Nanos"]
+	return path, hasNanos
+}
+
+/*
+leaf is a field Transform assigns as a whole, with every
+path it can be reached by and the struct declaring it.
+The fields of a value struct follow it, with of set to
+its qualified name.
+*/
+type leaf struct {
+	names  []string
+	typ    types.Type
+	parent *types.Struct
+	of     string
+}
+
+/*
+path is one of the names a struct is reached by; promoted
+paths keep the struct their fields are promoted into and
+the index of the field there, as Transform does
+*/
+type path struct {
+	prefix string
+	owner  *types.Struct
+	index  []int
+}
+
+func leavesOf(structType *types.Struct) (leaves []leaf) {
+	collectLeaves([]path{{}}, structType, "", make(map[*types.Struct]bool), &leaves)
+	return leaves
+}
+
+func collectLeaves(paths []path, structType *types.Struct, of string, visiting map[*types.Struct]bool, leaves *[]leaf) {
+	visiting[structType] = true
+	defer delete(visiting, structType)
+
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		name, ignored := fieldName(field, structType.Tag(i))
+		if ignored || (!field.Exported() && !field.Embedded()) {
+			continue
+		}
+
+		var named, promoted []path
+		for _, p := range paths {
+			index := append(append([]int{}, p.index...), i)
+			if p.owner != nil && !isPromoted(p.owner, field, index) {
+				continue
+			}
+			named = append(named, path{prefix: appendFieldName(p.prefix, name)})
+			if field.Embedded() {
+				if p.owner == nil {
+					promoted = append(promoted, path{p.prefix, structType, []int{i}})
+				} else {
+					promoted = append(promoted, path{p.prefix, p.owner, index})
+				}
+			}
+		}
+		fieldPaths := append(named, promoted...)
+		if len(fieldPaths) == 0 {
+			continue
+		}
+
+		nested, composite := deref(field.Type()).Underlying().(*types.Struct)
+		switch {
+		case composite && isValueStruct(deref(field.Type())):
+			if field.Exported() {
+				*leaves = append(*leaves, leaf{namesOf(fieldPaths), field.Type(), structType, of})
+			}
+			if !visiting[nested] {
+				collectLeaves(fieldPaths, nested, fieldPaths[0].prefix, visiting, leaves)
+			}
+		case composite && !isTime(field.Type()):
+			if !visiting[nested] {
+				collectLeaves(fieldPaths, nested, of, visiting, leaves)
+			}
+		case field.Exported():
+			*leaves = append(*leaves, leaf{namesOf(fieldPaths), field.Type(), structType, of})
+		}
+	}
+}
+
+/*
+isPromoted tells if the field at index of owner is the one its
+name selects, neither shadowed nor ambiguous
+*/
+func isPromoted(owner *types.Struct, field *types.Var, index []int) bool {
+	obj, selected, _ := types.LookupFieldOrMethod(owner, false, field.Pkg(), field.Name())
+	if _, isField := obj.(*types.Var); !isField || len(selected) != len(index) {
+		return false
+	}
+	for i := range index {
+		if selected[i] != index[i] {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+namesOf leaves out the promotion paths, as Transform does
+*/
+func namesOf(paths []path) (names []string) {
+	for _, p := range paths {
+		if p.owner == nil {
+			names = append(names, p.prefix)
+		}
+	}
+	return names
+}
+
+func fieldName(field *types.Var, tag string) (name string, ignored bool) {
+	switch tagged := reflect.StructTag(tag).Get(tagName); tagged {
+	case "-":
+		return "", true
+	case "":
+		return field.Name(), false
+	default:
+		return tagged, false
+	}
+}
+
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+func appendFieldName(prefix, name string) string {
+	if len(prefix) == 0 {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func deref(t types.Type) types.Type {
+	if pointer, ok := t.Underlying().(*types.Pointer); ok {
+		return pointer.Elem()
+	}
+	return t
+}
+
+var (
+	errorType       = types.Universe.Lookup("error").Type()
+	byteSlice       = types.NewSlice(types.Typ[types.Byte])
+	scanner         = newInterface("Scan", types.NewTuple(newVar(types.NewInterfaceType(nil, nil).Complete())), types.NewTuple(newVar(errorType)))
+	textMarshaler   = newInterface("MarshalText", nil, types.NewTuple(newVar(byteSlice), newVar(errorType)))
+	textUnmarshaler = newInterface("UnmarshalText", types.NewTuple(newVar(byteSlice)), types.NewTuple(newVar(errorType)))
+)
+
+func newInterface(method string, params, results *types.Tuple) *types.Interface {
+	signature := types.NewSignatureType(nil, nil, nil, params, results, false)
+	return types.NewInterfaceType([]*types.Func{types.NewFunc(token.NoPos, nil, method, signature)}, nil).Complete()
+}
+
+func newVar(t types.Type) *types.Var {
+	return types.NewParam(token.NoPos, nil, "", t)
+}
+
+/*
+isValueStruct tells if t is a struct implementing driver.Valuer,
+sql.Scanner or the encoding text interfaces, which Transform maps
+as a whole against single values and field by field against plain
+structs
+*/
+func isValueStruct(t types.Type) bool {
+	if _, isStruct := t.Underlying().(*types.Struct); !isStruct || isTime(t) {
+		return false
+	}
+	pointer := types.NewPointer(t)
+	if valuer := valuerOf(t); valuer != nil && (types.Implements(t, valuer) || types.Implements(pointer, valuer)) {
+		return true
+	}
+	return types.Implements(pointer, scanner) || types.Implements(t, textMarshaler) ||
+		types.Implements(pointer, textMarshaler) || types.Implements(pointer, textUnmarshaler)
+}
+
+/*
+valuerOf returns driver.Valuer built from the driver.Value the Value
+method of t returns, nil when t has no such method: the analyzed
+package need not import database/sql/driver itself
+*/
+func valuerOf(t types.Type) *types.Interface {
+	method, _, _ := types.LookupFieldOrMethod(t, true, nil, "Value")
+	fn, ok := method.(*types.Func)
+	if !ok {
+		return nil
+	}
+	results := fn.Type().(*types.Signature).Results()
+	if results.Len() != 2 {
+		return nil
+	}
+	value, ok := results.At(0).Type().(*types.Named)
+	if !ok || value.Obj().Pkg() == nil || value.Obj().Pkg().Path() != "database/sql/driver" || value.Obj().Name() != "Value" {
+		return nil
+	}
+	return newInterface("Value", nil, types.NewTuple(newVar(value), newVar(errorType)))
+}
+
+/*
+isSingleValue tells if a source of type t is assigned as a whole
+onto a value struct rather than filling it field by field
+*/
+func isSingleValue(t types.Type) bool {
+	t = deref(t)
+	_, isStruct := t.Underlying().(*types.Struct)
+	return !isStruct || isTime(t) || isValueStruct(t)
+}
+
+func isTime(t types.Type) bool {
+	named, ok := deref(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Time"
+}
+
+/*
+isTimestamp tells if structType holds a time as Seconds and Nanos,
+which Transform only converts when the other side is a time.Time
+*/
+func isTimestamp(structType *types.Struct) bool {
+	if structType == nil {
+		return false
+	}
+	var seconds, nanos bool
+	for i := 0; i < structType.NumFields(); i++ {
+		field := structType.Field(i)
+		basic, isBasic := field.Type().Underlying().(*types.Basic)
+		integer := isBasic && basic.Info()&types.IsInteger != 0 && basic.Info()&types.IsUnsigned == 0
+		seconds = seconds || field.Name() == "Seconds" && integer
+		nanos = nanos || field.Name() == "Nanos" && integer
+	}
+	return seconds && nanos
+}
+
+/*
+lossy tells if converting src to dst may overflow, lose the sign,
+truncate a fraction or lose precision
+*/
+func lossy(pass *analysis.Pass, src, dst types.Type) bool {
+	srcBasic, srcOk := deref(src).Underlying().(*types.Basic)
+	dstBasic, dstOk := deref(dst).Underlying().(*types.Basic)
+	if !srcOk || !dstOk || srcBasic.Info()&types.IsNumeric == 0 || dstBasic.Info()&types.IsNumeric == 0 {
+		return false
+	}
+	if srcBasic.Info()&types.IsComplex != 0 || dstBasic.Info()&types.IsComplex != 0 {
+		return false
+	}
+
+	srcBits := 8 * pass.TypesSizes.Sizeof(srcBasic)
+	dstBits := 8 * pass.TypesSizes.Sizeof(dstBasic)
+	srcFloat := srcBasic.Info()&types.IsFloat != 0
+	dstFloat := dstBasic.Info()&types.IsFloat != 0
+	srcUnsigned := srcBasic.Info()&types.IsUnsigned != 0
+	dstUnsigned := dstBasic.Info()&types.IsUnsigned != 0
+
+	switch {
+	case srcFloat && dstFloat:
+		return dstBits < srcBits
+	case srcFloat:
+		return true
+	case dstFloat:
+		mantissa := int64(24)
+		if dstBits == 64 {
+			mantissa = 53
+		}
+		return srcBits > mantissa
+	case !srcUnsigned && dstUnsigned:
+		return true
+	case srcUnsigned && !dstUnsigned:
+		return dstBits <= srcBits
+	default:
+		return dstBits < srcBits
+	}
+}
@@ -0,0 +1,12 @@
+package animagivet_test
+
+import (
+	"testing"
+
+	"github.com/barreeyentos/animagi/animagivet"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), animagivet.Analyzer, "transforms")
+}
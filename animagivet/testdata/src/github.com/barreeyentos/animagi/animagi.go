@@ -0,0 +1,15 @@
+package animagi
+
+type Option func()
+
+type Mapper struct{}
+
+func New(opts ...Option) *Mapper { return &Mapper{} }
+
+func WithMatcher(matcher interface{}) Option { return nil }
+
+func FuzzyMatch(maxRank uint) interface{} { return nil }
+
+func Transform(src, dst interface{}, opts ...Option) error { return nil }
+
+func (m *Mapper) Transform(src, dst interface{}, opts ...Option) error { return nil }
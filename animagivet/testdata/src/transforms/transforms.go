@@ -0,0 +1,119 @@
+package transforms
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/barreeyentos/animagi"
+)
+
+type Audit struct{ CreatedBy string }
+
+type Entity struct {
+	Audit
+	Name    string
+	Age     int64
+	Secret  string `animagi:"-"`
+	Weight  float64
+	When    time.Time
+	Address struct{ City string }
+	Notes   string
+}
+
+type DTO struct {
+	CreatedBy string
+	Name      string
+	Age       int32
+	Weight    float32
+	Missing   string
+	When      string
+	Address   *struct{ City string }
+	hidden    int
+}
+
+type Stats struct {
+	Seconds int64
+	Nanos   int32
+	Label   string
+}
+
+type StatsDTO struct {
+	Seconds int64
+	Nanos   int32
+	Label   string
+	Extra   string
+}
+
+type Timestamp struct {
+	Seconds int64
+	Nanos   int32
+}
+
+type Run struct{ Started time.Time }
+
+type RunDTO struct{ Started Timestamp }
+
+type Base struct{ Code string }
+
+type Mid struct{ Base }
+
+type Outer struct {
+	Code string
+	Mid
+}
+
+type A struct{ ID int }
+
+type B struct{ ID int }
+
+type Both struct {
+	A
+	B
+}
+
+type IDDTO struct{ ID int }
+
+type Money struct{ Amount int }
+
+func (Money) Value() int { return 0 }
+
+type Priced struct{ Price Money }
+
+type PricedDTO struct{ Price struct{ Amount int } }
+
+type JSONHome struct{ Street string }
+
+func (JSONHome) Value() (driver.Value, error) { return nil, nil }
+
+type Row struct{ Home JSONHome }
+
+type RowDTO struct{ Home struct{ Street string } }
+
+type StoredRow struct{ Home string }
+
+func transforms(entity Entity, unknown interface{}) {
+	var dto DTO
+	animagi.Transform(entity, &dto) // want "Age: converting int64 to int32 may lose data" "Weight: converting float64 to float32 may lose data" "Transform leaves Missing of transforms.DTO unmapped" "Transform drops Notes of transforms.Entity"
+	animagi.Transform(entity, dto)  // want "Transform cannot set dst of type transforms.DTO, pass a pointer"
+	animagi.Transform(unknown, &dto)
+	animagi.New().Transform(&entity, &entity)
+
+	var stats StatsDTO
+	animagi.Transform(Stats{}, &stats) // want "Transform leaves Extra of transforms.StatsDTO unmapped"
+	var run RunDTO
+	animagi.Transform(Run{}, &run)
+	animagi.Transform(run, &Run{})
+	animagi.Transform(Outer{}, &Outer{})
+	animagi.Transform(Both{}, &IDDTO{}) // want "Transform leaves ID of transforms.IDDTO unmapped" "Transform drops A.ID, B.ID of transforms.Both"
+	animagi.Transform(Priced{}, &PricedDTO{})
+	animagi.Transform(Row{}, &RowDTO{})
+	animagi.Transform(Row{}, &StoredRow{})
+	animagi.Transform(RowDTO{}, &Row{})
+
+	animagi.New(animagi.WithMatcher(animagi.FuzzyMatch(5))).Transform(entity, &dto)
+	animagi.Transform(entity, &dto, animagi.WithMatcher(animagi.FuzzyMatch(5)))
+
+	var small int8
+	animagi.Transform(300, &small)    // want "converting int to int8 may lose data"
+	animagi.Transform(entity, &small) // want "Transform cannot map transforms.Entity onto int8"
+}
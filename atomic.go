@@ -0,0 +1,56 @@
+package animagi
+
+import (
+	"reflect"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+/*
+isAtomic tells if values of the struct type t are mapped as a
+whole instead of being decomposed field by field
+*/
+func isAtomic(t reflect.Type) bool {
+	return t == timeType
+}
+
+/*
+isValueStruct tells if the struct type t is a database or text value,
+which is mapped as a whole against single values and other such values
+but field by field against plain structs
+*/
+func isValueStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !isAtomic(t) && (isSQLValue(t) || isTextValue(t))
+}
+
+/*
+isSingleValue tells if a source of type t is assigned as a whole
+onto a value struct rather than filling it field by field
+*/
+func isSingleValue(t reflect.Type) bool {
+	if t == nil {
+		return true
+	}
+	t = indirectType(t)
+	return t.Kind() != reflect.Struct || isAtomic(t) || isValueStruct(t)
+}
+
+/*
+isValueSource tells if val is a value struct, whose fields
+are described too for destinations that are plain structs
+*/
+func isValueSource(val sourceMatch) bool {
+	return val.FieldType != nil && isValueStruct(indirectType(val.FieldType))
+}
+
+/*
+isComposite tells if t, or what it points to, is a struct whose
+fields are mapped one by one
+*/
+func isComposite(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isAtomic(t)
+}
@@ -0,0 +1,344 @@
+package animagi
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+const (
+	unknownPath   = "path does not exist in "
+	pairedTwice   = "path is paired more than once: "
+	mismatchedSrc = "src does not match the mapping's type"
+)
+
+// ErrRoundTrip is reported for fields that change after a forward and a reverse mapping
+var ErrRoundTrip = errors.New("value did not survive the round trip")
+
+/*
+ConverterFunc converts a single field value while mapping
+*/
+type ConverterFunc func(interface{}) (interface{}, error)
+
+/*
+PathPair links a field of the left type to the field of the
+right type it is mapped to, with optional converters for each direction
+*/
+type PathPair struct {
+	Left    string
+	Right   string
+	Forward ConverterFunc
+	Reverse ConverterFunc
+}
+
+/*
+BiMapping defines the mapping between two types once and applies
+it in both directions.  Pairs are resolved a single time, from
+explicit pairs first and then the matching rules of Transform,
+so the forward and reverse mappings are always symmetric.
+
+Configure a BiMapping before its first use; it is safe for
+concurrent use afterwards.
+*/
+type BiMapping struct {
+	left         reflect.Type
+	right        reflect.Type
+	options      []Option
+	explicit     []PathPair
+	ignoredLeft  map[string]bool
+	ignoredRight map[string]bool
+
+	once  sync.Once
+	pairs []PathPair
+	err   error
+}
+
+/*
+NewBiMapping creates the mapping between the types of left and right,
+either of which may be a value or a pointer.
+The options are used in both directions.
+*/
+func NewBiMapping(left, right interface{}, opts ...Option) *BiMapping {
+	return &BiMapping{
+		left:         reflect.Indirect(reflect.ValueOf(left)).Type(),
+		right:        reflect.Indirect(reflect.ValueOf(right)).Type(),
+		options:      opts,
+		ignoredLeft:  make(map[string]bool),
+		ignoredRight: make(map[string]bool),
+	}
+}
+
+/*
+Pair maps leftPath onto rightPath and back, taking priority over matching by name
+*/
+func (m *BiMapping) Pair(leftPath, rightPath string) *BiMapping {
+	return m.PairConverted(leftPath, rightPath, nil, nil)
+}
+
+/*
+PairConverted pairs two paths whose values need converting.
+reverse must be the inverse of forward for round trips to hold.
+*/
+func (m *BiMapping) PairConverted(leftPath, rightPath string, forward, reverse ConverterFunc) *BiMapping {
+	m.explicit = append(m.explicit, PathPair{leftPath, rightPath, forward, reverse})
+	return m
+}
+
+/*
+IgnoreLeft keeps the field at path of the left type out of the mapping
+*/
+func (m *BiMapping) IgnoreLeft(path string) *BiMapping {
+	m.ignoredLeft[path] = true
+	return m
+}
+
+/*
+IgnoreRight keeps the field at path of the right type out of the mapping
+*/
+func (m *BiMapping) IgnoreRight(path string) *BiMapping {
+	m.ignoredRight[path] = true
+	return m
+}
+
+/*
+Pairs returns the resolved pairs, with every path fully qualified
+*/
+func (m *BiMapping) Pairs() ([]PathPair, error) {
+	m.once.Do(m.resolve)
+	return m.pairs, m.err
+}
+
+/*
+Forward maps src, a value of the left type, into dst
+*/
+func (m *BiMapping) Forward(src, dst interface{}) error {
+	return m.apply(src, dst, m.left, false)
+}
+
+/*
+Reverse maps src, a value of the right type, into dst
+*/
+func (m *BiMapping) Reverse(src, dst interface{}) error {
+	return m.apply(src, dst, m.right, true)
+}
+
+/*
+VerifyRoundTrip maps left forward into a new right value and back
+into a new left value, and reports every paired field that changed
+*/
+func (m *BiMapping) VerifyRoundTrip(left interface{}) error {
+	right := reflect.New(m.right)
+	if err := m.Forward(left, right.Interface()); err != nil {
+		return err
+	}
+	back := reflect.New(m.left)
+	if err := m.Reverse(right.Interface(), back.Interface()); err != nil {
+		return err
+	}
+
+	original := describeStructure(left)
+	roundTripped := describeStructure(back.Interface())
+	var errs FieldErrors
+	for _, pair := range m.pairs {
+		before, found := original[pair.Left]
+		after := roundTripped[pair.Left]
+		if found && !sameValue(before.FieldValue, after.FieldValue) {
+			errs.add(pair.Left, ErrRoundTrip)
+		}
+	}
+	return errs.orNil()
+}
+
+func (m *BiMapping) apply(src, dst interface{}, srcType reflect.Type, reverse bool) error {
+	if cannotModifyField(dst) {
+		return errors.New(dstError)
+	}
+	if findValueOf(src).Type() != srcType {
+		return errors.New(mismatchedSrc)
+	}
+	if _, err := m.Pairs(); err != nil {
+		return err
+	}
+
+	options := newOptions(append(m.options[:len(m.options):len(m.options)], WithMatcher(ExactMatch)))
+	srcDescription := describeStructure(src)
+	pairedDescription := make(map[string]typeDescription)
+	var errs FieldErrors
+
+	for _, pair := range m.pairs {
+		from, to, converter := pair.Left, pair.Right, pair.Forward
+		if reverse {
+			from, to, converter = pair.Right, pair.Left, pair.Reverse
+		}
+		val, found := srcDescription[from]
+		if !found {
+			continue
+		}
+		if converter != nil {
+			converted, err := converter(valueInterface(val.FieldValue))
+			if err != nil {
+				errs.add(to, err)
+				continue
+			}
+			val = typeDescription{reflect.TypeOf(converted), reflect.ValueOf(converted), val.Order}
+		}
+		pairedDescription[to] = val
+	}
+
+	mapToRoot(dst, pairedDescription, options, &errs)
+	return errs.orNil()
+}
+
+/*
+resolve pairs the explicit paths, then every right leaf with the left
+leaf the matcher picks, so apply only has to copy the pairs
+*/
+func (m *BiMapping) resolve() {
+	options := newOptions(m.options)
+	leftLeaves := leafNames(m.left)
+	rightLeaves := leafNames(m.right)
+	leftByName := indexLeaves(leftLeaves)
+	rightByName := indexLeaves(rightLeaves)
+	pairedLeft := make(map[string]bool)
+	pairedRight := make(map[string]bool)
+
+	for _, pair := range m.explicit {
+		left, leftFound := leftByName[pair.Left]
+		right, rightFound := rightByName[pair.Right]
+		if !leftFound {
+			m.err = errors.New(unknownPath + m.left.String() + ": " + pair.Left)
+			return
+		}
+		if !rightFound {
+			m.err = errors.New(unknownPath + m.right.String() + ": " + pair.Right)
+			return
+		}
+		if pairedLeft[left] {
+			m.err = errors.New(pairedTwice + pair.Left)
+			return
+		}
+		if pairedRight[right] {
+			m.err = errors.New(pairedTwice + pair.Right)
+			return
+		}
+		m.pairs = append(m.pairs, PathPair{left, right, pair.Forward, pair.Reverse})
+		pairedLeft[left] = true
+		pairedRight[right] = true
+	}
+
+	for _, names := range rightLeaves {
+		right := names[0]
+		if pairedRight[right] || isIgnored(names, m.ignoredRight) {
+			continue
+		}
+		var sources []string
+		for _, leftLeaf := range leftLeaves {
+			if !pairedLeft[leftLeaf[0]] && !isIgnored(leftLeaf, m.ignoredLeft) {
+				sources = append(sources, leftLeaf...)
+			}
+		}
+		sort.Strings(sources)
+		if source, _, found := options.matcher(names, sources, options.similarity.Rank); found {
+			left := leftByName[source]
+			m.pairs = append(m.pairs, PathPair{Left: left, Right: right})
+			pairedLeft[left] = true
+		}
+	}
+}
+
+/*
+leafNames lists, for every settable leaf field of structType,
+all the paths it can be reached by with the qualified one first
+*/
+func leafNames(structType reflect.Type) [][]string {
+	var names [][]string
+	for _, leaf := range leafTypes(structType) {
+		if len(leaf.of) == 0 {
+			names = append(names, leaf.names)
+		}
+	}
+	return names
+}
+
+/*
+typedLeaf is a settable leaf field of a type, with its paths
+and the struct it is declared in.  The fields of a value struct
+follow it, with of set to its qualified name.
+*/
+type typedLeaf struct {
+	names  []string
+	typ    reflect.Type
+	parent reflect.Type
+	of     string
+}
+
+func leafTypes(structType reflect.Type) []typedLeaf {
+	var leaves []typedLeaf
+	collectLeafNames(rootPaths, structType, "", map[reflect.Type]bool{}, &leaves)
+	return leaves
+}
+
+func collectLeafNames(paths []fieldPath, structType reflect.Type, of string, visiting map[reflect.Type]bool, leaves *[]typedLeaf) {
+	visiting[structType] = true
+	defer delete(visiting, structType)
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldPaths := pathsOfField(paths, structType, i)
+		if len(fieldPaths) == 0 || (len(field.PkgPath) != 0 && !field.Anonymous) {
+			continue
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch {
+		case isValueStruct(fieldType):
+			*leaves = append(*leaves, typedLeaf{namesOf(fieldPaths), field.Type, structType, of})
+			if !visiting[fieldType] {
+				collectLeafNames(fieldPaths, fieldType, fieldPaths[0].prefix, visiting, leaves)
+			}
+		case isComposite(fieldType):
+			if !visiting[fieldType] {
+				collectLeafNames(fieldPaths, fieldType, of, visiting, leaves)
+			}
+		default:
+			*leaves = append(*leaves, typedLeaf{namesOf(fieldPaths), field.Type, structType, of})
+		}
+	}
+}
+
+/*
+indexLeaves maps every name of a leaf to its qualified name
+*/
+func indexLeaves(leaves [][]string) map[string]string {
+	byName := make(map[string]string)
+	for _, names := range leaves {
+		for _, name := range names {
+			byName[name] = names[0]
+		}
+	}
+	return byName
+}
+
+func isIgnored(names []string, ignored map[string]bool) bool {
+	for _, name := range names {
+		if ignored[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func valueInterface(val reflect.Value) interface{} {
+	if !val.IsValid() || !val.CanInterface() {
+		return nil
+	}
+	return val.Interface()
+}
+
+func sameValue(a, b reflect.Value) bool {
+	return reflect.DeepEqual(valueInterface(reflect.Indirect(a)), valueInterface(reflect.Indirect(b)))
+}
@@ -0,0 +1,165 @@
+package animagi_test
+
+import (
+	"errors"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type OrderEntity struct {
+	ID       int
+	Customer struct {
+		Name string
+	}
+	TotalCents int
+	Secret     string
+}
+
+type OrderDTO struct {
+	ID           int64
+	CustomerName string `animagi:"Customer.Name"`
+	Total        float64
+	Secret       string `animagi:"-"`
+}
+
+type Contact struct {
+	Name  string
+	Email string
+}
+
+type ContactDTO struct {
+	Name  string
+	Emial string
+	Nme2  string
+}
+
+var _ = Describe("BiMapping", func() {
+
+	var mapping *animagi.BiMapping
+
+	BeforeEach(func() {
+		mapping = animagi.NewBiMapping(OrderEntity{}, &OrderDTO{}).
+			PairConverted("TotalCents", "Total",
+				func(v interface{}) (interface{}, error) { return float64(v.(int)) / 100, nil },
+				func(v interface{}) (interface{}, error) { return int(v.(float64) * 100), nil })
+	})
+
+	entity := func() OrderEntity {
+		var e OrderEntity
+		e.ID = 42
+		e.Customer.Name = "Jane"
+		e.TotalCents = 1250
+		e.Secret = "hidden"
+		return e
+	}
+
+	Context("Resolving pairs", func() {
+		It("Should pair explicit, tagged and same named fields", func() {
+			pairs, err := mapping.Pairs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pairs).To(HaveLen(3))
+			Expect(pairs[0].Left).To(Equal("TotalCents"))
+			Expect(pairs[0].Right).To(Equal("Total"))
+			Expect(pairs[1].Left).To(Equal("ID"))
+			Expect(pairs[2].Left).To(Equal("Customer.Name"))
+			Expect(pairs[2].Right).To(Equal("Customer.Name"))
+		})
+
+		It("Should report unknown paths", func() {
+			_, err := animagi.NewBiMapping(OrderEntity{}, OrderDTO{}).Pair("Nope", "ID").Pairs()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should leave ignored fields out", func() {
+			pairs, err := animagi.NewBiMapping(OrderEntity{}, OrderDTO{}).IgnoreLeft("ID").Pairs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pairs).To(HaveLen(1))
+			Expect(pairs[0].Left).To(Equal("Customer.Name"))
+		})
+
+		It("Should reject paths paired twice", func() {
+			_, err := animagi.NewBiMapping(OrderEntity{}, OrderDTO{}).Pair("ID", "ID").Pair("ID", "Total").Pairs()
+			Expect(err).To(HaveOccurred())
+			_, err = animagi.NewBiMapping(OrderEntity{}, OrderDTO{}).Pair("ID", "ID").Pair("TotalCents", "ID").Pairs()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should pair fields with the matcher", func() {
+			pairs, err := animagi.NewBiMapping(Contact{}, ContactDTO{}, animagi.WithMatcher(animagi.FuzzyMatch(10))).Pairs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pairs).To(Equal([]animagi.PathPair{{Left: "Name", Right: "Name"}, {Left: "Email", Right: "Emial"}}))
+		})
+	})
+
+	Context("Applying in both directions", func() {
+		It("Should map forward", func() {
+			var dto OrderDTO
+			err := mapping.Forward(entity(), &dto)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dto.ID).To(BeNumerically("==", 42))
+			Expect(dto.CustomerName).To(Equal("Jane"))
+			Expect(dto.Total).To(Equal(12.5))
+			Expect(dto.Secret).To(BeEmpty())
+		})
+
+		It("Should map in reverse with the same pairs", func() {
+			dto := OrderDTO{42, "Jane", 12.5, "leak"}
+			var back OrderEntity
+			err := mapping.Reverse(dto, &back)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(back.ID).To(Equal(42))
+			Expect(back.Customer.Name).To(Equal("Jane"))
+			Expect(back.TotalCents).To(Equal(1250))
+			Expect(back.Secret).To(BeEmpty())
+		})
+
+		It("Should only fill paired fields", func() {
+			fuzzy := animagi.NewBiMapping(Contact{}, ContactDTO{}, animagi.WithMatcher(animagi.FuzzyMatch(10)))
+			var dto ContactDTO
+			err := fuzzy.Forward(Contact{"Jane", "jane@example.com"}, &dto)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dto).To(Equal(ContactDTO{Name: "Jane", Emial: "jane@example.com"}))
+
+			var back Contact
+			err = fuzzy.Reverse(ContactDTO{"Jane", "jane@example.com", "Janet"}, &back)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(back).To(Equal(Contact{"Jane", "jane@example.com"}))
+		})
+
+		It("Should reject sources of the wrong type", func() {
+			var dto OrderDTO
+			err := mapping.Forward(OrderDTO{}, &dto)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should report converter errors with the destination path", func() {
+			failing := animagi.NewBiMapping(OrderEntity{}, OrderDTO{}).
+				PairConverted("TotalCents", "Total",
+					func(v interface{}) (interface{}, error) { return nil, errors.New("boom") }, nil)
+			var dto OrderDTO
+			err := failing.Forward(entity(), &dto)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("Total"))
+		})
+	})
+
+	Context("Round trips", func() {
+		It("Should verify symmetric mappings", func() {
+			Expect(mapping.VerifyRoundTrip(entity())).To(Succeed())
+		})
+
+		It("Should report fields that do not survive the round trip", func() {
+			lossy := animagi.NewBiMapping(OrderEntity{}, OrderDTO{}).
+				PairConverted("TotalCents", "Total",
+					func(v interface{}) (interface{}, error) { return float64(v.(int)) / 100, nil },
+					func(v interface{}) (interface{}, error) { return int(v.(float64)), nil })
+			err := lossy.VerifyRoundTrip(entity())
+			Expect(err).To(HaveOccurred())
+			Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("TotalCents"))
+			Expect(err.(animagi.FieldErrors)[0].Err).To(Equal(animagi.ErrRoundTrip))
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package animagi
+
+import (
+	"sort"
+)
+
+/*
+SegmentRank is the share of a single segment in a SimilarityRank.
+Target or Candidate is empty when the segment is missing from that
+path, costing a depth difference.
+*/
+type SegmentRank struct {
+	Target    string
+	Candidate string
+	Rank      uint
+}
+
+/*
+Candidate is a path ranked against a target by RankCandidates.
+Ambiguous is set on the best candidates when more than one of them
+is within a single wrong letter of the best rank.
+*/
+type Candidate struct {
+	Path      string
+	Rank      uint
+	Segments  []SegmentRank
+	Ambiguous bool
+}
+
+/*
+RankCandidates ranks candidates against target with SimilarityRank and
+returns the best n of them, all of them when n is not positive.
+Candidates with the same rank keep their order.
+*/
+func RankCandidates(target string, candidates []string, n int) []Candidate {
+	return DefaultSimilarityOptions.RankCandidates(target, candidates, n)
+}
+
+/*
+RankCandidates is RankCandidates with the costs of similarity
+*/
+func (similarity SimilarityOptions) RankCandidates(target string, candidates []string, n int) []Candidate {
+	ranked := make([]Candidate, len(candidates))
+	for i, path := range candidates {
+		segments, valid := similarity.segments(target, path)
+		ranked[i] = Candidate{Path: path, Rank: MaxRank}
+		if valid {
+			ranked[i] = Candidate{Path: path, Segments: segments}
+			for _, segment := range segments {
+				ranked[i].Rank = addRank(ranked[i].Rank, segment.Rank)
+			}
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Rank < ranked[j].Rank
+	})
+
+	if len(ranked) > 1 && ranked[0].Rank != MaxRank && ranked[1].Rank <= addRank(ranked[0].Rank, similarity.Letter) {
+		for i := range ranked {
+			if ranked[i].Rank > addRank(ranked[0].Rank, similarity.Letter) {
+				break
+			}
+			ranked[i].Ambiguous = true
+		}
+	}
+
+	if n > 0 && n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
@@ -0,0 +1,63 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RankCandidates", func() {
+
+	candidates := []string{"Customer.Name", "Customer.Address.City", "Shipping.City", "Billing.Cty", "Town"}
+
+	It("Should return the best candidates first", func() {
+		ranked := animagi.RankCandidates("Shipping.City", candidates, 3)
+		Expect(ranked).To(HaveLen(3))
+		Expect(ranked[0].Path).To(Equal("Shipping.City"))
+		Expect(ranked[0].Rank).To(BeZero())
+		Expect(ranked[0].Ambiguous).To(BeFalse())
+		for i, candidate := range ranked {
+			Expect(candidate.Rank).To(Equal(animagi.SimilarityRank("Shipping.City", candidate.Path)))
+			if i > 0 {
+				Expect(candidate.Rank).To(BeNumerically(">=", ranked[i-1].Rank))
+			}
+		}
+	})
+
+	It("Should return every candidate when n is not positive", func() {
+		Expect(animagi.RankCandidates("City", candidates, 0)).To(HaveLen(len(candidates)))
+		Expect(animagi.RankCandidates("City", nil, 3)).To(BeEmpty())
+	})
+
+	It("Should break the rank down by segment", func() {
+		ranked := animagi.RankCandidates("Address.Cty", []string{"Customer.Address.City"}, 1)
+		Expect(ranked[0].Segments).To(Equal([]animagi.SegmentRank{
+			{Target: "", Candidate: "Customer", Rank: 3},
+			{Target: "Address", Candidate: "Address", Rank: 0},
+			{Target: "Cty", Candidate: "City", Rank: 7},
+		}))
+		Expect(ranked[0].Rank).To(BeNumerically("==", 10))
+	})
+
+	It("Should flag tied and near-tied best candidates", func() {
+		ranked := animagi.RankCandidates("Customer.City", candidates, 3)
+		Expect(ranked[0].Path).To(Equal("Customer.Address.City"))
+		Expect(ranked[1].Path).To(Equal("Customer.Name"))
+		Expect(ranked[0].Ambiguous).To(BeTrue())
+		Expect(ranked[1].Ambiguous).To(BeTrue())
+		Expect(ranked[2].Ambiguous).To(BeFalse())
+
+		ranked = animagi.RankCandidates("Home.City", []string{"Work.City", "Shop.City", "Town"}, 0)
+		Expect(ranked[0].Ambiguous).To(BeTrue())
+		Expect(ranked[1].Ambiguous).To(BeTrue())
+		Expect(ranked[2].Ambiguous).To(BeFalse())
+	})
+
+	It("Should rank invalid paths last", func() {
+		ranked := animagi.RankCandidates("City", []string{"bad..path", "City"}, 0)
+		Expect(ranked[0].Path).To(Equal("City"))
+		Expect(ranked[1].Rank).To(Equal(animagi.MaxRank))
+		Expect(ranked[1].Segments).To(BeNil())
+	})
+})
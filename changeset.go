@@ -0,0 +1,145 @@
+package animagi
+
+import (
+	"errors"
+	"reflect"
+)
+
+const mismatchedChange = "change does not fit dst"
+
+/*
+Change is a single destination field modified by Transform.
+SourcePath is the source field that supplied the new value;
+it is empty when a pointer was allocated to hold nested fields.
+*/
+type Change struct {
+	Path       string
+	SourcePath string
+	Old        interface{}
+	New        interface{}
+	field      []int
+}
+
+/*
+ChangeSet lists the changes made by Transform in the order they
+were made.  Its Inverse applied to the same destination rolls the
+Transform back.
+*/
+type ChangeSet []Change
+
+/*
+TransformWithChanges is Transform that also returns every
+destination field it modified along with the previous value
+*/
+func TransformWithChanges(src, dst interface{}, opts ...Option) (ChangeSet, error) {
+	return defaultMapper.TransformWithChanges(src, dst, opts...)
+}
+
+func recordChanges(changes *ChangeSet) Option {
+	return func(o *options) {
+		o.changes = changes
+	}
+}
+
+/*
+Inverse returns the changes that undo changes,
+in the order they have to be applied
+*/
+func (changes ChangeSet) Inverse() ChangeSet {
+	inverse := make(ChangeSet, len(changes))
+	for i, change := range changes {
+		change.Old, change.New = change.New, change.Old
+		inverse[len(changes)-1-i] = change
+	}
+	return inverse
+}
+
+/*
+Apply sets the new value of every change on dst, which must be
+settable and of the type the changes were recorded on.
+Pointers are copied so dst never shares memory with the change set.
+*/
+func (changes ChangeSet) Apply(dst interface{}) error {
+	if cannotModifyField(dst) {
+		return errors.New(dstError)
+	}
+
+	root := reflect.ValueOf(dst).Elem()
+	var errs FieldErrors
+	for _, change := range changes {
+		field, ok := fieldByIndex(root, change.field)
+		if !ok || !field.CanSet() {
+			errs.add(change.Path, errors.New(mismatchedChange))
+			continue
+		}
+
+		value := reflect.ValueOf(change.New)
+		switch {
+		case !value.IsValid():
+			value = reflect.Zero(field.Type())
+		case !value.Type().AssignableTo(field.Type()):
+			errs.add(change.Path, errors.New(mismatchedChange))
+			continue
+		case value.Kind() == reflect.Ptr && !value.IsNil():
+			copied := reflect.New(value.Type().Elem())
+			copied.Elem().Set(value.Elem())
+			value = copied
+		}
+		field.Set(value)
+	}
+	return errs.orNil()
+}
+
+/*
+fieldByIndex walks index from root allocating nil pointers on the way
+*/
+func fieldByIndex(root reflect.Value, index []int) (reflect.Value, bool) {
+	field := root
+	for _, i := range index {
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				if !field.CanSet() {
+					return field, false
+				}
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.Struct || i >= field.NumField() {
+			return field, false
+		}
+		field = field.Field(i)
+	}
+	return field, true
+}
+
+func (changes *ChangeSet) snapshot(field reflect.Value) interface{} {
+	if changes == nil {
+		return nil
+	}
+	return field.Interface()
+}
+
+func (changes *ChangeSet) len() int {
+	if changes == nil {
+		return 0
+	}
+	return len(*changes)
+}
+
+/*
+record inserts the change of field at position at, so a pointer
+allocated for nested fields is listed before the fields in it
+*/
+func (changes *ChangeSet) record(at int, path fieldPath, old interface{}, field reflect.Value, sourcePath string) {
+	if changes == nil {
+		return
+	}
+	current := field.Interface()
+	if reflect.DeepEqual(old, current) {
+		return
+	}
+	*changes = append(*changes, Change{})
+	copy((*changes)[at+1:], (*changes)[at:])
+	(*changes)[at] = Change{path.prefix, sourcePath, old, current, path.field}
+}
@@ -0,0 +1,106 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Shipment struct {
+	Tracking string
+	Weight   int
+	Dest     *Address
+}
+
+var _ = Describe("ChangeSet", func() {
+
+	var entity Shipment
+
+	BeforeEach(func() {
+		entity = Shipment{"TRK1", 10, nil}
+	})
+
+	It("Should list only the fields that changed", func() {
+		src := struct {
+			Tracking string
+			Weight   int
+		}{"TRK1", 12}
+
+		changes, err := animagi.TransformWithChanges(src, &entity)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes).To(HaveLen(1))
+		Expect(changes[0].Path).To(Equal("Weight"))
+		Expect(changes[0].SourcePath).To(Equal("Weight"))
+		Expect(changes[0].Old).To(Equal(10))
+		Expect(changes[0].New).To(Equal(12))
+	})
+
+	It("Should record the source path that supplied the value", func() {
+		src := struct {
+			Code string `animagi:"Tracking"`
+		}{"TRK2"}
+
+		changes, err := animagi.TransformWithChanges(src, &entity)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changes[0].Path).To(Equal("Tracking"))
+		Expect(changes[0].SourcePath).To(Equal("Tracking"))
+	})
+
+	It("Should roll back with the inverse", func() {
+		original := entity
+		src := struct {
+			Tracking string
+			Weight   int
+			Dest     Address
+		}{"TRK9", 99, Address{"Main St", "Springfield"}}
+
+		changes, err := animagi.TransformWithChanges(src, &entity)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entity.Dest.City).To(Equal("Springfield"))
+		Expect(changes[2].Path).To(Equal("Dest"))
+
+		err = changes.Inverse().Apply(&entity)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entity).To(Equal(original))
+	})
+
+	It("Should replay changes onto another destination without sharing memory", func() {
+		src := struct {
+			Weight int
+			Dest   Address
+		}{5, Address{City: "Shelbyville"}}
+
+		changes, err := animagi.TransformWithChanges(src, &entity)
+		Expect(err).NotTo(HaveOccurred())
+
+		var replayed Shipment
+		err = changes.Apply(&replayed)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replayed.Weight).To(Equal(5))
+		Expect(replayed.Dest.City).To(Equal("Shelbyville"))
+		Expect(replayed.Dest).NotTo(BeIdenticalTo(entity.Dest))
+	})
+
+	It("Should roll back interface fields", func() {
+		dst := struct{ V interface{} }{1}
+		src := struct{ V string }{"new"}
+
+		changes, err := animagi.TransformWithChanges(src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.V).To(Equal("new"))
+
+		err = changes.Inverse().Apply(&dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.V).To(Equal(1))
+	})
+
+	It("Should refuse changes recorded on another type", func() {
+		src := struct{ Weight int }{5}
+		changes, _ := animagi.TransformWithChanges(src, &entity)
+
+		var other struct{ Weight string }
+		err := changes.Apply(&other)
+		Expect(err).To(HaveOccurred())
+	})
+})
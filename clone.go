@@ -0,0 +1,109 @@
+package animagi
+
+import (
+	"reflect"
+)
+
+/*
+Clone returns a deep copy of v: slices, maps, pointers, interfaces
+and nested structs are duplicated so the copy shares no memory with v.
+Pointers reached more than once are copied once, keeping cycles intact.
+Unexported fields cannot be reached safely and are copied as they are.
+*/
+func Clone(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return make(copier).copy(reflect.ValueOf(v)).Interface()
+}
+
+/*
+WithDeepCopy makes Transform deep copy the slices, maps and pointers
+it assigns instead of sharing them with src
+*/
+func WithDeepCopy() Option {
+	return func(o *options) {
+		o.deepCopy = true
+	}
+}
+
+type visit struct {
+	pointer uintptr
+	typ     reflect.Type
+}
+
+// copier remembers the pointers already copied
+type copier map[visit]reflect.Value
+
+func (c copier) copy(v reflect.Value) reflect.Value {
+	if !v.IsValid() || !v.CanInterface() {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		seen := visit{v.Pointer(), v.Type()}
+		if copied, found := c[seen]; found {
+			return copied
+		}
+		copied := reflect.New(v.Type().Elem())
+		c[seen] = copied
+		copied.Elem().Set(c.copy(v.Elem()))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(c.copy(v.Elem()))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Cap())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for entries := v.MapRange(); entries.Next(); {
+			copied.SetMapIndex(c.copy(entries.Key()), c.copy(entries.Value()))
+		}
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(c.copy(v.Field(i)))
+			}
+		}
+		return copied
+	default:
+		return v
+	}
+}
+
+/*
+copyOf deep copies v when asked for with WithDeepCopy
+*/
+func (o *options) copyOf(v reflect.Value) reflect.Value {
+	if !o.deepCopy {
+		return v
+	}
+	return make(copier).copy(v)
+}
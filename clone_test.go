@@ -0,0 +1,100 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Playlist struct {
+	Name   string
+	Tracks []string
+	Plays  map[string]int
+	Owner  *Address
+	Next   *Playlist
+	Extra  interface{}
+	hidden []int
+}
+
+func newPlaylist() Playlist {
+	return Playlist{
+		Name:   "road trip",
+		Tracks: []string{"one", "two"},
+		Plays:  map[string]int{"one": 3},
+		Owner:  &Address{"Main St", "Springfield"},
+		Extra:  []int{1, 2},
+		hidden: []int{7},
+	}
+}
+
+var _ = Describe("Deep copies", func() {
+
+	Context("Clone", func() {
+		It("Should not share memory with the original", func() {
+			original := newPlaylist()
+			clone := animagi.Clone(original).(Playlist)
+			Expect(clone).To(Equal(original))
+
+			clone.Tracks[0] = "changed"
+			clone.Plays["one"] = 10
+			clone.Owner.City = "Shelbyville"
+			clone.Extra.([]int)[0] = 9
+			Expect(original.Tracks[0]).To(Equal("one"))
+			Expect(original.Plays["one"]).To(Equal(3))
+			Expect(original.Owner.City).To(Equal("Springfield"))
+			Expect(original.Extra.([]int)[0]).To(Equal(1))
+		})
+
+		It("Should keep cycles", func() {
+			original := newPlaylist()
+			original.Next = &original
+			clone := animagi.Clone(&original).(*Playlist)
+			Expect(clone).NotTo(BeIdenticalTo(&original))
+			Expect(clone.Next).To(BeIdenticalTo(clone))
+		})
+
+		It("Should keep nil values", func() {
+			Expect(animagi.Clone(nil)).To(BeNil())
+			clone := animagi.Clone(Playlist{}).(Playlist)
+			Expect(clone.Tracks).To(BeNil())
+			Expect(clone.Plays).To(BeNil())
+			Expect(clone.Owner).To(BeNil())
+		})
+	})
+
+	Context("Transform", func() {
+		It("Should share slices and maps by default", func() {
+			original := newPlaylist()
+			var dst Playlist
+			err := animagi.Transform(original, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			dst.Tracks[0] = "changed"
+			Expect(original.Tracks[0]).To(Equal("changed"))
+		})
+
+		It("Should deep copy when asked", func() {
+			original := newPlaylist()
+			var dst Playlist
+			err := animagi.Transform(original, &dst, animagi.WithDeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Tracks).To(Equal(original.Tracks))
+
+			dst.Tracks[0] = "changed"
+			dst.Plays["one"] = 10
+			dst.Owner.City = "Shelbyville"
+			Expect(original.Tracks[0]).To(Equal("one"))
+			Expect(original.Plays["one"]).To(Equal(3))
+			Expect(original.Owner.City).To(Equal("Springfield"))
+		})
+
+		It("Should deep copy slices mapped at the root", func() {
+			original := []string{"one"}
+			var dst []string
+			err := animagi.Transform(original, &dst, animagi.WithDeepCopy())
+			Expect(err).NotTo(HaveOccurred())
+			dst[0] = "changed"
+			Expect(original[0]).To(Equal("one"))
+		})
+	})
+})
@@ -0,0 +1,15 @@
+/*
+Command animagivet checks animagi.Transform calls, run it with
+
+	go vet -vettool=$(which animagivet) ./...
+*/
+package main
+
+import (
+	"github.com/barreeyentos/animagi/animagivet"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(animagivet.Analyzer)
+}
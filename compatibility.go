@@ -0,0 +1,194 @@
+package animagi
+
+import (
+	"reflect"
+)
+
+/*
+TransformReport tells what Transform between two types would do.
+Compatible is false when their kinds differ and Transform would fail.
+DstCoverage is the percentage of destination fields that would be
+set and SrcCoverage the percentage of source fields that would be read.
+Unmapped lists the destination fields no source matches, Unused the
+source fields nothing reads and Unconvertible the destination fields
+whose source cannot be converted, which Transform leaves untouched
+and whose source counts as unused.
+*/
+type TransformReport struct {
+	Compatible    bool
+	DstCoverage   float64
+	SrcCoverage   float64
+	Unmapped      []string
+	Unused        []string
+	Unconvertible []string
+}
+
+/*
+CanTransform reports what Transform from a srcType onto a dstType
+would cover without needing values of either.  Conversions that
+depend on the value, such as parsing a string, may still fail.
+*/
+func CanTransform(srcType, dstType reflect.Type) TransformReport {
+	return defaultMapper.CanTransform(srcType, dstType)
+}
+
+/*
+CanTransform reports what Transform would cover with the rules of the Mapper
+*/
+func (m *Mapper) CanTransform(srcType, dstType reflect.Type) (report TransformReport) {
+	srcType, dstType = indirectType(srcType), indirectType(dstType)
+	if srcType.Kind() != dstType.Kind() {
+		return report
+	}
+	report.Compatible = true
+
+	options := m.callOptions(srcType, dstType, nil)
+	options.plan = nil
+	if srcType.Kind() != reflect.Struct {
+		if !options.convertible(srcType, dstType) {
+			report.Unconvertible = []string{""}
+			return report
+		}
+		report.DstCoverage, report.SrcCoverage = 100, 100
+		return report
+	}
+
+	var srcLeaves []typedLeaf
+	srcDescription := make(map[string]typeDescription)
+	leafOf := make(map[string]int)
+	for order, leaf := range leafTypes(srcType) {
+		i := len(srcLeaves)
+		if len(leaf.of) != 0 {
+			i = leafOf[leaf.of]
+		} else {
+			srcLeaves = append(srcLeaves, leaf)
+		}
+		for _, name := range leaf.names {
+			srcDescription[name] = typeDescription{FieldType: leaf.typ, Order: order}
+			leafOf[name] = i
+		}
+	}
+
+	read := make(map[int]bool)
+	whole := make(map[string]bool)
+	covered, considered := 0, 0
+	for _, leaf := range leafTypes(dstType) {
+		rule, ruled := options.memberRule(leaf.names)
+		if rule.ignore || whole[leaf.of] {
+			continue
+		}
+		if isValueStruct(indirectType(leaf.typ)) && !ruled {
+			match, found := options.findSource(leaf.names, srcDescription)
+			if whole[leaf.names[0]] = found && isSingleValue(match.FieldType); !whole[leaf.names[0]] {
+				continue
+			}
+		}
+		considered++
+		if rule.compute != nil {
+			covered++
+			continue
+		}
+
+		path, found := rule.from, false
+		if ruled {
+			_, found = srcDescription[path]
+		} else {
+			var match sourceMatch
+			match, found = options.findSource(leaf.names, srcDescription)
+			path = match.Path
+		}
+		if !found && indirectType(leaf.typ) == timeType {
+			if match, fromTimestamp := options.timestampSource(leaf.names, srcDescription); fromTimestamp {
+				read[leafOf[match.Path+".Seconds"]], read[leafOf[match.Path+".Nanos"]] = true, true
+				covered++
+				continue
+			}
+		}
+		if !found && isTimestamp(leaf.parent) {
+			if match, fromTime := options.timeSourceOf(leaf.names, srcDescription); fromTime {
+				read[leafOf[match.Path]] = true
+				covered++
+				continue
+			}
+		}
+		if !found {
+			report.Unmapped = append(report.Unmapped, leaf.names[0])
+			continue
+		}
+
+		if !options.convertible(srcDescription[path].FieldType, leaf.typ) {
+			report.Unconvertible = append(report.Unconvertible, leaf.names[0])
+			continue
+		}
+		read[leafOf[path]] = true
+		covered++
+	}
+
+	for i, leaf := range srcLeaves {
+		if !read[i] {
+			report.Unused = append(report.Unused, leaf.names[0])
+		}
+	}
+	report.DstCoverage = percentage(covered, considered)
+	report.SrcCoverage = percentage(len(read), len(srcLeaves))
+	return report
+}
+
+/*
+timeSourceOf finds the time.Time a field of a timestamp struct
+reached by names is set from
+*/
+func (o *options) timeSourceOf(names []string, srcDescription map[string]typeDescription) (sourceMatch, bool) {
+	parents := make([]string, len(names))
+	for i, name := range names {
+		parents[i] = parentPath(name)
+	}
+	match, found := o.findSource(parents, srcDescription)
+	return match, found && indirectType(match.FieldType) == timeType
+}
+
+func percentage(part, whole int) float64 {
+	if whole == 0 {
+		return 100
+	}
+	return 100 * float64(part) / float64(whole)
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+/*
+convertible tells if setValueOfDst can set a dstType from a srcType
+*/
+func (o *options) convertible(srcType, dstType reflect.Type) bool {
+	srcType, dstType = indirectType(srcType), indirectType(dstType)
+	if _, found := o.converters[planKey{srcType, dstType}]; found || srcType == dstType {
+		return true
+	}
+	if implements(srcType, valuerType) || reflect.PtrTo(dstType).Implements(scannerType) {
+		return true
+	}
+	if srcType == timeType || dstType == timeType {
+		other := dstType
+		if dstType == timeType {
+			other = srcType
+		}
+		return other.Kind() == reflect.String || isInt(other.Kind()) || isUint(other.Kind()) || isTimestamp(other)
+	}
+	if o.enums[srcType] != nil && dstType.Kind() == reflect.String || o.enums[dstType] != nil && srcType.Kind() == reflect.String {
+		return true
+	}
+
+	hasText := implements(srcType, textMarshalerType) || o.stringer && implements(srcType, stringerType)
+	if reflect.PtrTo(dstType).Implements(textUnmarshalerType) && (hasText || srcType.Kind() == reflect.String) {
+		return true
+	}
+	if dstType.Kind() == reflect.String && hasText {
+		return true
+	}
+	return srcType.ConvertibleTo(dstType)
+}
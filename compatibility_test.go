@@ -0,0 +1,83 @@
+package animagi_test
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type StockItem struct {
+	SKU      string
+	Quantity int64
+	Tags     []string
+	Added    time.Time
+	Supplier Address
+}
+
+type StockItemDTO struct {
+	SKU      string
+	Quantity int32
+	Tags     map[string]bool
+	Added    string
+	Supplier *Address
+	Price    float64
+}
+
+var _ = Describe("CanTransform", func() {
+
+	itemType := reflect.TypeOf(StockItem{})
+	dtoType := reflect.TypeOf(&StockItemDTO{})
+
+	It("Should report incompatible kinds", func() {
+		report := animagi.CanTransform(itemType, reflect.TypeOf(""))
+		Expect(report.Compatible).To(BeFalse())
+
+		var dst string
+		Expect(animagi.Transform(StockItem{}, &dst)).To(HaveOccurred())
+	})
+
+	It("Should report coverage and the fields left out", func() {
+		report := animagi.CanTransform(itemType, dtoType)
+		Expect(report.Compatible).To(BeTrue())
+		Expect(report.Unmapped).To(Equal([]string{"Price"}))
+		Expect(report.Unconvertible).To(Equal([]string{"Tags"}))
+		Expect(report.Unused).To(Equal([]string{"Tags"}))
+		Expect(report.DstCoverage).To(BeNumerically("~", 100*5.0/7))
+		Expect(report.SrcCoverage).To(BeNumerically("~", 100*5.0/6))
+	})
+
+	It("Should agree with Transform", func() {
+		item := StockItem{"A-1", 3, []string{"new"}, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Address{"Main St", "Springfield"}}
+		var dto StockItemDTO
+		Expect(animagi.Transform(item, &dto)).To(Succeed())
+		Expect(dto.Quantity).To(BeNumerically("==", 3))
+		Expect(dto.Added).To(Equal("2020-01-02T00:00:00Z"))
+		Expect(dto.Supplier.City).To(Equal("Springfield"))
+		Expect(dto.Tags).To(BeNil())
+	})
+
+	It("Should use the rules of the mapper", func() {
+		mapper := animagi.New(animagi.WithConverter(reflect.TypeOf([]string{}), reflect.TypeOf(map[string]bool{}), func(v interface{}) (interface{}, error) {
+			return map[string]bool{}, nil
+		}))
+		mapper.Configure(StockItem{}, StockItemDTO{}).ForMember("Price", animagi.Ignore())
+
+		report := mapper.CanTransform(itemType, dtoType)
+		Expect(report.Unmapped).To(BeEmpty())
+		Expect(report.Unconvertible).To(BeEmpty())
+		Expect(report.DstCoverage).To(BeNumerically("==", 100))
+		Expect(report.SrcCoverage).To(BeNumerically("==", 100))
+	})
+
+	It("Should check values that are not structs", func() {
+		Expect(animagi.CanTransform(reflect.TypeOf(int64(0)), reflect.TypeOf(time.Duration(0))).DstCoverage).To(BeNumerically("==", 100))
+		Expect(animagi.CanTransform(reflect.TypeOf(int64(0)), reflect.TypeOf(int8(0))).Compatible).To(BeFalse())
+		report := animagi.CanTransform(reflect.TypeOf([]string{}), reflect.TypeOf([]int{}))
+		Expect(report.Compatible).To(BeTrue())
+		Expect(report.Unconvertible).To(Equal([]string{""}))
+	})
+})
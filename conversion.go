@@ -0,0 +1,210 @@
+package animagi
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+/*
+ConversionPolicy decides what happens when a numeric
+value does not fit into its destination field
+*/
+type ConversionPolicy int
+
+const (
+	// ConversionAllow converts the same way Go does, silently truncating
+	ConversionAllow ConversionPolicy = iota
+	// ConversionError leaves the field untouched and reports an error
+	ConversionError
+	// ConversionSaturate clamps the value to the closest one dst can hold
+	ConversionSaturate
+)
+
+var (
+	// ErrOverflow is reported when a value is out of the range of dst (NaN included)
+	ErrOverflow = errors.New("value overflows dst")
+	// ErrSignLoss is reported when a negative value is mapped to an unsigned dst
+	ErrSignLoss = errors.New("negative value cannot be stored in unsigned dst")
+	// ErrTruncation is reported when a float with a fractional part is mapped to an integer dst
+	ErrTruncation = errors.New("fractional part would be truncated")
+	// ErrPrecisionLoss is reported when an integer cannot be represented exactly by a float dst
+	ErrPrecisionLoss = errors.New("value cannot be represented exactly in dst")
+)
+
+func isInt(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Int64
+}
+
+func isUint(kind reflect.Kind) bool {
+	return kind >= reflect.Uint && kind <= reflect.Uintptr
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return isInt(kind) || isUint(kind) || isFloat(kind)
+}
+
+/*
+checkedConvert converts the numeric src into a new value of dstType.
+Under ConversionSaturate only overflow and sign loss are clamped;
+fractions are truncated and floats rounded as Go would.
+*/
+func checkedConvert(src reflect.Value, dstType reflect.Type, policy ConversionPolicy) (reflect.Value, error) {
+	dst := reflect.New(dstType).Elem()
+	var err error
+
+	switch {
+	case isInt(src.Kind()):
+		err = setFromInt(dst, src.Int(), policy)
+	case isUint(src.Kind()):
+		err = setFromUint(dst, src.Uint(), policy)
+	case isFloat(src.Kind()):
+		err = setFromFloat(dst, src.Float(), policy)
+	}
+	return dst, err
+}
+
+func setFromInt(dst reflect.Value, v int64, policy ConversionPolicy) error {
+	switch {
+	case isInt(dst.Kind()):
+		if dst.OverflowInt(v) {
+			if policy != ConversionSaturate {
+				return ErrOverflow
+			}
+			v = clampInt(v < 0, dst.Type().Bits())
+		}
+		dst.SetInt(v)
+	case isUint(dst.Kind()):
+		if v < 0 {
+			if policy != ConversionSaturate {
+				return ErrSignLoss
+			}
+			v = 0
+		}
+		return setFromUint(dst, uint64(v), policy)
+	case isFloat(dst.Kind()):
+		exact := new(big.Float).SetPrec(mantissaBits(dst.Type())).SetInt64(v)
+		if exact.Acc() != big.Exact && policy == ConversionError {
+			return ErrPrecisionLoss
+		}
+		dst.SetFloat(float64(v))
+	}
+	return nil
+}
+
+func setFromUint(dst reflect.Value, v uint64, policy ConversionPolicy) error {
+	switch {
+	case isInt(dst.Kind()):
+		if v > math.MaxInt64 || dst.OverflowInt(int64(v)) {
+			if policy != ConversionSaturate {
+				return ErrOverflow
+			}
+			dst.SetInt(clampInt(false, dst.Type().Bits()))
+			return nil
+		}
+		dst.SetInt(int64(v))
+	case isUint(dst.Kind()):
+		if dst.OverflowUint(v) {
+			if policy != ConversionSaturate {
+				return ErrOverflow
+			}
+			v = clampUint(dst.Type().Bits())
+		}
+		dst.SetUint(v)
+	case isFloat(dst.Kind()):
+		exact := new(big.Float).SetPrec(mantissaBits(dst.Type())).SetUint64(v)
+		if exact.Acc() != big.Exact && policy == ConversionError {
+			return ErrPrecisionLoss
+		}
+		dst.SetFloat(float64(v))
+	}
+	return nil
+}
+
+func setFromFloat(dst reflect.Value, f float64, policy ConversionPolicy) error {
+	if math.IsNaN(f) && !isFloat(dst.Kind()) {
+		if policy != ConversionSaturate {
+			return ErrOverflow
+		}
+		f = 0
+	}
+
+	truncated := math.Trunc(f)
+
+	switch {
+	case isInt(dst.Kind()):
+		if truncated < math.MinInt64 || truncated >= math.MaxInt64 || dst.OverflowInt(int64(truncated)) {
+			if policy != ConversionSaturate {
+				return ErrOverflow
+			}
+			dst.SetInt(clampInt(f < 0, dst.Type().Bits()))
+			return nil
+		}
+		if truncated != f && policy == ConversionError {
+			return ErrTruncation
+		}
+		dst.SetInt(int64(truncated))
+	case isUint(dst.Kind()):
+		if truncated < 0 {
+			if policy != ConversionSaturate {
+				return ErrSignLoss
+			}
+			truncated = 0
+		}
+		if truncated >= math.MaxUint64 || dst.OverflowUint(uint64(truncated)) {
+			if policy != ConversionSaturate {
+				return ErrOverflow
+			}
+			dst.SetUint(clampUint(dst.Type().Bits()))
+			return nil
+		}
+		if truncated != f && policy == ConversionError {
+			return ErrTruncation
+		}
+		dst.SetUint(uint64(truncated))
+	case isFloat(dst.Kind()):
+		if dst.OverflowFloat(f) {
+			if policy != ConversionSaturate {
+				return ErrOverflow
+			}
+			f = math.Copysign(math.MaxFloat32, f)
+		}
+		dst.SetFloat(f)
+	}
+	return nil
+}
+
+func isIntegerToString(src reflect.Value, dstType reflect.Type) bool {
+	return (isInt(src.Kind()) || isUint(src.Kind())) && dstType.Kind() == reflect.String
+}
+
+func formatInteger(src reflect.Value) string {
+	if isUint(src.Kind()) {
+		return strconv.FormatUint(src.Uint(), 10)
+	}
+	return strconv.FormatInt(src.Int(), 10)
+}
+
+func clampInt(negative bool, bits int) int64 {
+	if negative {
+		return -1 << uint(bits-1)
+	}
+	return 1<<uint(bits-1) - 1
+}
+
+func clampUint(bits int) uint64 {
+	return math.MaxUint64 >> uint(64-bits)
+}
+
+func mantissaBits(floatType reflect.Type) uint {
+	if floatType.Kind() == reflect.Float32 {
+		return 24
+	}
+	return 53
+}
@@ -0,0 +1,165 @@
+package animagi_test
+
+import (
+	"math"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type BigNumbers struct {
+	I   int64
+	U   uint64
+	F   float64
+	Neg int
+}
+
+type SmallNumbers struct {
+	I   int8
+	U   int64
+	F   int32
+	Neg uint16
+}
+
+var _ = Describe("Conversion", func() {
+
+	Context("Default policy", func() {
+		It("Should truncate like Go does", func() {
+			src := BigNumbers{I: 1 << 40, F: 3.7, Neg: -1}
+			var dst SmallNumbers
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.I).To(BeZero())
+			Expect(dst.F).To(BeNumerically("==", 3))
+			Expect(dst.Neg).To(BeNumerically("==", math.MaxUint16))
+		})
+	})
+
+	Context("ConversionError policy", func() {
+		checked := animagi.WithConversionPolicy(animagi.ConversionError)
+
+		It("Should report integer overflow", func() {
+			src := BigNumbers{I: 1 << 40}
+			var dst SmallNumbers
+			err := animagi.Transform(src, &dst, checked)
+			Expect(err).To(HaveOccurred())
+			errs := err.(animagi.FieldErrors)
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Path).To(Equal("I"))
+			Expect(errs[0].Err).To(Equal(animagi.ErrOverflow))
+			Expect(dst.I).To(BeZero())
+		})
+
+		It("Should report sign loss", func() {
+			src := BigNumbers{Neg: -1}
+			var dst SmallNumbers
+			err := animagi.Transform(src, &dst, checked)
+			Expect(err.(animagi.FieldErrors)[0].Err).To(Equal(animagi.ErrSignLoss))
+		})
+
+		It("Should report fractional truncation", func() {
+			src := BigNumbers{F: 3.7}
+			var dst SmallNumbers
+			err := animagi.Transform(src, &dst, checked)
+			Expect(err.(animagi.FieldErrors)[0].Err).To(Equal(animagi.ErrTruncation))
+			Expect(dst.F).To(BeZero())
+		})
+
+		It("Should report precision loss into floats", func() {
+			src := struct{ F int64 }{1<<53 + 1}
+			var dst struct{ F float64 }
+			err := animagi.Transform(src, &dst, checked)
+			Expect(err.(animagi.FieldErrors)[0].Err).To(Equal(animagi.ErrPrecisionLoss))
+		})
+
+		It("Should allow conversions that fit", func() {
+			src := BigNumbers{I: -12, U: 99, F: 4.0, Neg: 7}
+			var dst SmallNumbers
+			err := animagi.Transform(src, &dst, checked)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst).To(Equal(SmallNumbers{-12, 99, 4, 7}))
+		})
+
+		It("Should tag nested errors with the field path and keep mapping", func() {
+			src := struct {
+				Outer struct{ Small int }
+				Fine  int
+				Neg   int
+			}{Fine: 7, Neg: -3}
+			src.Outer.Small = 300
+
+			var dst struct {
+				Outer struct{ Small int8 }
+				Fine  int16
+				Neg   *uint
+			}
+
+			err := animagi.Transform(src, &dst, checked)
+			Expect(err).To(HaveOccurred())
+			errs := err.(animagi.FieldErrors)
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Path).To(Equal("Outer.Small"))
+			Expect(errs[1].Path).To(Equal("Neg"))
+			Expect(err.Error()).To(ContainSubstring("Outer.Small: "))
+			Expect(dst.Fine).To(BeNumerically("==", 7))
+			Expect(dst.Neg).To(BeNil())
+		})
+	})
+
+	Context("ConversionSaturate policy", func() {
+		saturate := animagi.WithConversionPolicy(animagi.ConversionSaturate)
+
+		It("Should clamp to the closest representable value", func() {
+			src := BigNumbers{I: 1 << 40, U: math.MaxUint64, F: -1e10, Neg: -12}
+			var dst SmallNumbers
+			err := animagi.Transform(src, &dst, saturate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.I).To(BeNumerically("==", math.MaxInt8))
+			Expect(dst.U).To(BeNumerically("==", math.MaxInt64))
+			Expect(dst.F).To(BeNumerically("==", math.MinInt32))
+			Expect(dst.Neg).To(BeZero())
+		})
+
+		It("Should still truncate fractions", func() {
+			src := BigNumbers{F: 3.7}
+			var dst SmallNumbers
+			err := animagi.Transform(src, &dst, saturate)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.F).To(BeNumerically("==", 3))
+		})
+	})
+
+	Context("Integers to strings", func() {
+		src := struct {
+			Code  int
+			Count uint8
+		}{65, 7}
+
+		It("Should format integers as decimals", func() {
+			var dst struct {
+				Code  string
+				Count mystring
+			}
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Code).To(Equal("65"))
+			Expect(dst.Count).To(BeEquivalentTo("7"))
+		})
+
+		It("Should format into string pointers", func() {
+			var dst struct{ Code *string }
+			err := animagi.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*dst.Code).To(Equal("65"))
+		})
+
+		It("Should keep the rune conversion behind an option", func() {
+			var dst struct{ Code string }
+			err := animagi.Transform(src, &dst, animagi.WithRuneConversion())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Code).To(Equal("A"))
+		})
+	})
+})
@@ -0,0 +1,126 @@
+package animagi
+
+import (
+	"reflect"
+)
+
+/*
+ChangeKind tells how a path differs between two values
+*/
+type ChangeKind int
+
+const (
+	// Added paths only exist in the new value
+	Added ChangeKind = iota
+	// Removed paths only exist in the old value
+	Removed
+	// Modified paths exist in both values but hold different values
+	Modified
+)
+
+func (kind ChangeKind) String() string {
+	switch kind {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	default:
+		return "modified"
+	}
+}
+
+/*
+Difference is a single path that differs between two values.
+Path is the qualified path in the new value and OldPath the one
+in the old value; they only differ when fields were matched by a
+promoted name or a tag.  Pointers are compared by what they point to.
+*/
+type Difference struct {
+	Kind    ChangeKind
+	Path    string
+	OldPath string
+	Old     interface{}
+	New     interface{}
+}
+
+/*
+Diff flattens old and new into dotted paths the way Transform sees
+them and reports every path that was added, removed or modified.
+Paths are matched with the same rules Transform uses so old and new
+may be different types, such as two versions of an entity.
+Unexported fields are not compared.  A nil old or new value has no
+paths, and values that are not structs have the single root path "".
+*/
+func Diff(old, new interface{}) []Difference {
+	return defaultMapper.Diff(old, new)
+}
+
+/*
+Diff compares old and new matching paths with the rules of the Mapper
+*/
+func (m *Mapper) Diff(old, new interface{}) (differences []Difference) {
+	options := m.options
+	oldLeaves := diffLeaves(old)
+	newLeaves := diffLeaves(new)
+
+	oldDescription := make(map[string]typeDescription)
+	oldLeafOf := make(map[string]int)
+	for i, leaf := range oldLeaves {
+		for _, name := range leaf.names {
+			oldDescription[name] = leaf.typeDescription
+			oldLeafOf[name] = i
+		}
+	}
+
+	matchedOld := make(map[int]bool)
+	for _, leaf := range newLeaves {
+		if !leaf.FieldValue.CanInterface() {
+			continue
+		}
+		newValue := valueInterface(reflect.Indirect(leaf.FieldValue))
+
+		match, found := options.findSource(leaf.names, oldDescription)
+		if found && matchedOld[oldLeafOf[match.Path]] {
+			found = false
+		}
+		if !found {
+			differences = append(differences, Difference{Kind: Added, Path: leaf.names[0], New: newValue})
+			continue
+		}
+
+		oldLeaf := oldLeaves[oldLeafOf[match.Path]]
+		matchedOld[oldLeafOf[match.Path]] = true
+		if !sameValue(oldLeaf.FieldValue, leaf.FieldValue) {
+			differences = append(differences, Difference{Modified, leaf.names[0], oldLeaf.names[0], valueInterface(reflect.Indirect(oldLeaf.FieldValue)), newValue})
+		}
+	}
+
+	for i, leaf := range oldLeaves {
+		if !matchedOld[i] && leaf.FieldValue.CanInterface() {
+			differences = append(differences, Difference{Kind: Removed, OldPath: leaf.names[0], Old: valueInterface(reflect.Indirect(leaf.FieldValue))})
+		}
+	}
+	return differences
+}
+
+/*
+diffLeaves describes the leaves of value, none when it is nil and
+the value itself at the root path when it is not a struct
+*/
+func diffLeaves(value interface{}) []describedLeaf {
+	valueOf := findValueOf(value)
+	switch {
+	case !valueOf.IsValid():
+		return nil
+	case valueOf.Kind() == reflect.Struct && !isAtomic(valueOf.Type()) && !isValueStruct(valueOf.Type()):
+		var leaves []describedLeaf
+		for _, leaf := range describeLeaves(value) {
+			if !leaf.part {
+				leaves = append(leaves, leaf)
+			}
+		}
+		return leaves
+	default:
+		return []describedLeaf{{[]string{""}, false, typeDescription{valueOf.Type(), valueOf, 0}}}
+	}
+}
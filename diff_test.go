@@ -0,0 +1,89 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type ProfileV1 struct {
+	Name    string
+	Age     int
+	Address Address
+	Fax     string
+}
+
+type ProfileV2 struct {
+	Base
+	Age      int
+	Address  Address
+	Nickname *string
+}
+
+var _ = Describe("Diff", func() {
+
+	It("Should report nothing for equal values", func() {
+		profile := ProfileV1{"Jane", 40, Address{"Main St", "Springfield"}, "555"}
+		Expect(animagi.Diff(profile, profile)).To(BeEmpty())
+	})
+
+	It("Should report modified nested paths with old and new values", func() {
+		old := ProfileV1{"Jane", 40, Address{"Main St", "Springfield"}, "555"}
+		new := old
+		new.Address.City = "Shelbyville"
+		new.Age = 41
+
+		differences := animagi.Diff(old, new)
+		Expect(differences).To(HaveLen(2))
+		Expect(differences[0]).To(Equal(animagi.Difference{animagi.Modified, "Age", "Age", 40, 41}))
+		Expect(differences[1]).To(Equal(animagi.Difference{animagi.Modified, "Address.City", "Address.City", "Springfield", "Shelbyville"}))
+	})
+
+	It("Should match paths across types like Transform does", func() {
+		nickname := "JJ"
+		old := ProfileV1{"Jane", 40, Address{"Main St", "Springfield"}, "555"}
+		new := ProfileV2{Base{7, "Janet"}, 40, Address{"Main St", "Springfield"}, &nickname}
+
+		differences := animagi.Diff(old, new)
+		Expect(differences).To(ConsistOf(
+			animagi.Difference{Kind: animagi.Added, Path: "Base.ID", New: 7},
+			animagi.Difference{animagi.Modified, "Base.Name", "Name", "Jane", "Janet"},
+			animagi.Difference{Kind: animagi.Added, Path: "Nickname", New: "JJ"},
+			animagi.Difference{Kind: animagi.Removed, OldPath: "Fax", Old: "555"},
+		))
+	})
+
+	It("Should compare pointers by the values they point to", func() {
+		first, second := "same", "same"
+		old := struct{ P *string }{&first}
+		new := struct{ P *string }{&second}
+		Expect(animagi.Diff(old, new)).To(BeEmpty())
+
+		new.P = nil
+		differences := animagi.Diff(old, new)
+		Expect(differences).To(HaveLen(1))
+		Expect(differences[0].Old).To(Equal("same"))
+		Expect(differences[0].New).To(BeNil())
+		Expect(differences[0].Kind.String()).To(Equal("modified"))
+	})
+
+	It("Should report every path of a nil value as added or removed", func() {
+		profile := ProfileV1{"Jane", 40, Address{"Main St", "Springfield"}, "555"}
+		var missing *ProfileV1
+
+		added := animagi.Diff(nil, profile)
+		Expect(added).To(HaveLen(5))
+		Expect(added[0]).To(Equal(animagi.Difference{Kind: animagi.Added, Path: "Name", New: "Jane"}))
+		removed := animagi.Diff(profile, missing)
+		Expect(removed).To(HaveLen(5))
+		Expect(removed[0]).To(Equal(animagi.Difference{Kind: animagi.Removed, OldPath: "Name", Old: "Jane"}))
+		Expect(animagi.Diff(nil, nil)).To(BeEmpty())
+	})
+
+	It("Should compare values that are not structs at the root path", func() {
+		Expect(animagi.Diff(1, 1)).To(BeEmpty())
+		Expect(animagi.Diff(1, 2)).To(Equal([]animagi.Difference{{animagi.Modified, "", "", 1, 2}}))
+		Expect(animagi.Diff(nil, "new")).To(Equal([]animagi.Difference{{Kind: animagi.Added, New: "new"}}))
+	})
+})
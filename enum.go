@@ -0,0 +1,98 @@
+package animagi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+/*
+ErrUnknownEnum is reported for enum values that have no name,
+and for names that are no value of the enum
+*/
+var ErrUnknownEnum = errors.New("value is not part of the enum")
+
+/*
+enum holds the names of the values of an integer enum type
+*/
+type enum struct {
+	names  map[int64]string
+	values map[string]int64
+}
+
+/*
+WithEnum registers the values of an integer enum, such as
+type Status int with a String method, so they are mapped
+by name onto strings and parsed back from them.
+Values may be of several enum types.
+*/
+func WithEnum(values ...fmt.Stringer) Option {
+	return func(o *options) {
+		enums := make(map[reflect.Type]*enum, len(o.enums)+1)
+		for enumType, existing := range o.enums {
+			enums[enumType] = existing
+		}
+		for _, value := range values {
+			v := reflect.ValueOf(value)
+			if !isInt(v.Kind()) && !isUint(v.Kind()) {
+				continue
+			}
+			registered := enums[v.Type()]
+			if registered == nil || registered == o.enums[v.Type()] {
+				registered = registered.copy()
+				enums[v.Type()] = registered
+			}
+			registered.names[enumNumber(v)] = value.String()
+			registered.values[value.String()] = enumNumber(v)
+		}
+		o.enums = enums
+	}
+}
+
+func (e *enum) copy() *enum {
+	copied := &enum{make(map[int64]string), make(map[string]int64)}
+	if e != nil {
+		for number, name := range e.names {
+			copied.names[number] = name
+		}
+		for name, number := range e.values {
+			copied.values[name] = number
+		}
+	}
+	return copied
+}
+
+func enumNumber(v reflect.Value) int64 {
+	if isUint(v.Kind()) {
+		return int64(v.Uint())
+	}
+	return v.Int()
+}
+
+/*
+convertEnum maps registered enums onto strings by name and strings
+onto registered enums by value, reporting whether it handled the field
+*/
+func (o *options) convertEnum(dst, src reflect.Value) (converted bool, err error) {
+	if e, found := o.enums[src.Type()]; found && dst.Kind() == reflect.String {
+		name, known := e.names[enumNumber(src)]
+		if !known {
+			return true, ErrUnknownEnum
+		}
+		dst.SetString(name)
+		return true, nil
+	}
+	if e, found := o.enums[dst.Type()]; found && src.Kind() == reflect.String {
+		number, known := e.values[src.String()]
+		if !known {
+			return true, ErrUnknownEnum
+		}
+		if isUint(dst.Kind()) {
+			dst.SetUint(uint64(number))
+		} else {
+			dst.SetInt(number)
+		}
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,78 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type OrderStatus int
+
+const (
+	Pending OrderStatus = iota
+	Shipped
+	Delivered
+)
+
+func (s OrderStatus) String() string {
+	switch s {
+	case Pending:
+		return "pending"
+	case Shipped:
+		return "shipped"
+	case Delivered:
+		return "delivered"
+	}
+	return "unknown"
+}
+
+type APIStatus string
+
+var _ = Describe("Enums", func() {
+
+	statuses := animagi.WithEnum(Pending, Shipped, Delivered)
+
+	It("Should map integer enums onto strings by name", func() {
+		var dst struct {
+			Status   APIStatus
+			Previous *string
+		}
+		err := animagi.Transform(struct{ Status, Previous OrderStatus }{Delivered, Shipped}, &dst, statuses)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Status).To(Equal(APIStatus("delivered")))
+		Expect(*dst.Previous).To(Equal("shipped"))
+	})
+
+	It("Should map strings onto integer enums by name", func() {
+		var dst struct{ Status OrderStatus }
+		err := animagi.Transform(struct{ Status APIStatus }{"shipped"}, &dst, statuses)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Status).To(Equal(Shipped))
+	})
+
+	It("Should report values that do not map", func() {
+		var dst struct{ Status, Next OrderStatus }
+		err := animagi.Transform(struct{ Status, Next string }{"lost", "pending"}, &dst, statuses)
+		Expect(err).To(HaveOccurred())
+		errs := err.(animagi.FieldErrors)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Path).To(Equal("Status"))
+		Expect(errs[0].Err).To(Equal(animagi.ErrUnknownEnum))
+
+		var name struct{ Status string }
+		err = animagi.Transform(struct{ Status OrderStatus }{7}, &name, statuses)
+		Expect(err.(animagi.FieldErrors)[0].Err).To(Equal(animagi.ErrUnknownEnum))
+	})
+
+	It("Should let mappers add values", func() {
+		mapper := animagi.New(animagi.WithEnum(Pending))
+		var dst struct{ Status string }
+		err := mapper.Transform(struct{ Status OrderStatus }{Shipped}, &dst, animagi.WithEnum(Shipped))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Status).To(Equal("shipped"))
+
+		err = mapper.Transform(struct{ Status OrderStatus }{Shipped}, &dst)
+		Expect(err).To(HaveOccurred())
+	})
+})
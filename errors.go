@@ -0,0 +1,50 @@
+package animagi
+
+import (
+	"strings"
+)
+
+/*
+FieldError ties an error that occurred while mapping
+a single field to the dotted path of the destination
+field it happened on.
+*/
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+/*
+FieldErrors is returned by Transform when one or more
+fields could not be mapped.  Mapping continues past
+a failed field so every problem is reported at once.
+*/
+type FieldErrors []*FieldError
+
+func (errs FieldErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (errs *FieldErrors) add(path string, err error) {
+	if err != nil {
+		*errs = append(*errs, &FieldError{path, err})
+	}
+}
+
+func (errs FieldErrors) orNil() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
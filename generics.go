@@ -0,0 +1,76 @@
+//go:build go1.18
+// +build go1.18
+
+package animagi
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+)
+
+/*
+Map transforms src into a new D
+*/
+func Map[S, D any](src S, opts ...Option) (D, error) {
+	var dst D
+	err := Transform(src, &dst, opts...)
+	return dst, err
+}
+
+/*
+MapInto transforms src into dst; the compiler guarantees
+dst is a pointer so only a nil dst can fail at runtime
+*/
+func MapInto[S, D any](src S, dst *D, opts ...Option) error {
+	if dst == nil {
+		return errors.New(dstError)
+	}
+	return Transform(src, dst, opts...)
+}
+
+/*
+MapSlice transforms every element of src into a new D.
+Every element is mapped even when some fail; their errors
+are returned together with paths prefixed by the index, as in [2].Name
+*/
+func MapSlice[S, D any](src []S, opts ...Option) ([]D, error) {
+	if src == nil {
+		return nil, nil
+	}
+
+	dst := make([]D, len(src))
+	var errs FieldErrors
+	for i := range src {
+		err := Transform(src[i], &dst[i], opts...)
+		if err == nil {
+			continue
+		}
+		index := "[" + strconv.Itoa(i) + "]"
+		if fieldErrs, ok := err.(FieldErrors); ok {
+			for _, fieldErr := range fieldErrs {
+				errs.add(appendIndex(index, fieldErr.Path), fieldErr.Err)
+			}
+		} else {
+			errs.add(index, err)
+		}
+	}
+	return dst, errs.orNil()
+}
+
+/*
+WithParser converts strings mapped onto a T with a Parse-style
+constructor such as uuid.Parse; its errors are reported for the field
+*/
+func WithParser[T any](parse func(string) (T, error)) Option {
+	return WithConverter(reflect.TypeOf(""), reflect.TypeOf((*T)(nil)).Elem(), func(v interface{}) (interface{}, error) {
+		return parse(v.(string))
+	})
+}
+
+func appendIndex(index, path string) string {
+	if len(path) == 0 {
+		return index
+	}
+	return index + "." + path
+}
@@ -0,0 +1,75 @@
+//go:build go1.18
+// +build go1.18
+
+package animagi_test
+
+import (
+	"errors"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type PersonDTO struct {
+	Name string
+	Age  int8
+}
+
+var _ = Describe("Generics", func() {
+
+	src := Customer{Name: "Jane", Age: 40}
+
+	It("Should map into a new value", func() {
+		dto, err := animagi.Map[Customer, PersonDTO](src)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dto).To(Equal(PersonDTO{"Jane", 40}))
+	})
+
+	It("Should map into an existing value", func() {
+		dto := PersonDTO{Name: "old", Age: 1}
+		err := animagi.MapInto(src, &dto)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dto).To(Equal(PersonDTO{"Jane", 40}))
+	})
+
+	It("Should refuse a nil destination", func() {
+		var dto *PersonDTO
+		err := animagi.MapInto(src, dto)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should map slices", func() {
+		dtos, err := animagi.MapSlice[Customer, PersonDTO]([]Customer{src, {Name: "John", Age: 9}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dtos).To(Equal([]PersonDTO{{"Jane", 40}, {"John", 9}}))
+	})
+
+	It("Should prefix slice errors with the element index", func() {
+		customers := []Customer{src, {Name: "Old", Age: 300}}
+		dtos, err := animagi.MapSlice[Customer, PersonDTO](customers, animagi.WithConversionPolicy(animagi.ConversionError))
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("[1].Age"))
+		Expect(dtos).To(HaveLen(2))
+		Expect(dtos[1].Name).To(Equal("Old"))
+	})
+
+	It("Should parse strings with a parser", func() {
+		parseColor := func(name string) (Color, error) {
+			switch name {
+			case "red":
+				return 0, nil
+			case "green":
+				return 1, nil
+			}
+			return 0, errors.New("unknown color " + name)
+		}
+
+		var dst struct{ Fill, Stroke Color }
+		err := animagi.Transform(struct{ Fill, Stroke string }{"green", "blue"}, &dst, animagi.WithParser(parseColor))
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("Stroke"))
+		Expect(dst.Fill).To(Equal(Color(1)))
+	})
+})
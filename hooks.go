@@ -0,0 +1,62 @@
+package animagi
+
+import (
+	"reflect"
+)
+
+/*
+FieldContext describes a single field assignment: the destination
+path, the source path it resolved to and the SimilarityRank between them
+*/
+type FieldContext struct {
+	Path       string
+	SourcePath string
+	Rank       uint
+}
+
+/*
+BeforeFieldFunc runs before a destination field is assigned.
+Returning skip leaves the field untouched, returning an error
+also leaves it untouched and reports the error for the field.
+*/
+type BeforeFieldFunc func(field FieldContext, src, dst reflect.Value) (skip bool, err error)
+
+/*
+AfterFieldFunc runs once a destination field was assigned.
+dst is settable so the hook may normalize the value.
+*/
+type AfterFieldFunc func(field FieldContext, dst reflect.Value)
+
+/*
+WithBeforeField adds a hook run before every field assignment;
+hooks run in the order they were added until one skips
+*/
+func WithBeforeField(hook BeforeFieldFunc) Option {
+	return func(o *options) {
+		o.beforeHooks = append(o.beforeHooks[:len(o.beforeHooks):len(o.beforeHooks)], hook)
+	}
+}
+
+/*
+WithAfterField adds a hook run after every field assignment
+*/
+func WithAfterField(hook AfterFieldFunc) Option {
+	return func(o *options) {
+		o.afterHooks = append(o.afterHooks[:len(o.afterHooks):len(o.afterHooks)], hook)
+	}
+}
+
+func (o *options) beforeField(field FieldContext, src, dst reflect.Value) (skip bool, err error) {
+	for _, hook := range o.beforeHooks {
+		if skip, err = hook(field, src, dst); skip || err != nil {
+			return skip, err
+		}
+	}
+	return false, nil
+}
+
+func (o *options) afterField(field FieldContext, dst reflect.Value) {
+	for _, hook := range o.afterHooks {
+		hook(field, dst)
+	}
+}
@@ -0,0 +1,87 @@
+package animagi_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Account struct {
+	Username string
+	Password string
+	Address  Address
+}
+
+var _ = Describe("Field hooks", func() {
+
+	src := Account{"  jane  ", "hunter2", Address{"Main St", " Springfield "}}
+
+	It("Should call before hooks with the resolved paths", func() {
+		var seen []animagi.FieldContext
+		record := animagi.WithBeforeField(func(field animagi.FieldContext, src, dst reflect.Value) (bool, error) {
+			seen = append(seen, field)
+			return false, nil
+		})
+
+		var dst struct {
+			Login    string `animagi:"Username"`
+			Password string
+			Address  Address
+		}
+		err := animagi.Transform(src, &dst, record)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(seen).To(Equal([]animagi.FieldContext{
+			{Path: "Username", SourcePath: "Username"},
+			{Path: "Password", SourcePath: "Password"},
+			{Path: "Address.Street", SourcePath: "Address.Street"},
+			{Path: "Address.City", SourcePath: "Address.City"},
+		}))
+	})
+
+	It("Should let before hooks veto assignments", func() {
+		noSecrets := animagi.WithBeforeField(func(field animagi.FieldContext, src, dst reflect.Value) (bool, error) {
+			return field.Path == "Password", nil
+		})
+
+		var dst Account
+		err := animagi.Transform(src, &dst, noSecrets)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Password).To(BeEmpty())
+		Expect(dst.Username).To(Equal(src.Username))
+	})
+
+	It("Should report before hook errors with the field path", func() {
+		failing := animagi.WithBeforeField(func(field animagi.FieldContext, src, dst reflect.Value) (bool, error) {
+			if field.Path == "Address.City" {
+				return false, errors.New("not allowed")
+			}
+			return false, nil
+		})
+
+		var dst Account
+		err := animagi.Transform(src, &dst, failing)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("Address.City"))
+		Expect(dst.Address.City).To(BeEmpty())
+		Expect(dst.Address.Street).To(Equal("Main St"))
+	})
+
+	It("Should let after hooks normalize values", func() {
+		trim := animagi.WithAfterField(func(field animagi.FieldContext, dst reflect.Value) {
+			if dst.Kind() == reflect.String {
+				dst.SetString(strings.TrimSpace(dst.String()))
+			}
+		})
+
+		var dst Account
+		err := animagi.Transform(src, &dst, trim)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Username).To(Equal("jane"))
+		Expect(dst.Address.City).To(Equal("Springfield"))
+	})
+})
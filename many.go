@@ -0,0 +1,146 @@
+package animagi
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/*
+Source wraps a value given to TransformMany so its
+paths are looked up under Prefix, as in Prefix.Name
+*/
+type Source struct {
+	Prefix string
+	Value  interface{}
+}
+
+/*
+Prefixed puts every path of value under prefix
+*/
+func Prefixed(prefix string, value interface{}) Source {
+	return Source{prefix, value}
+}
+
+/*
+SourcePrecedence decides which of several sources supplying
+the same path is used
+*/
+type SourcePrecedence int
+
+const (
+	// FirstSourceWins keeps the value of the earliest source
+	FirstSourceWins SourcePrecedence = iota
+	// LastSourceWins keeps the value of the latest source
+	LastSourceWins
+)
+
+/*
+SourceConflict is reported, when asked for with WithConflictReporting,
+for fields several sources supplied different values for,
+listing their positions among the srcs of TransformMany
+*/
+type SourceConflict struct {
+	SourcePath string
+	Sources    []int
+}
+
+func (c *SourceConflict) Error() string {
+	sources := make([]string, len(c.Sources))
+	for i, source := range c.Sources {
+		sources[i] = strconv.Itoa(source)
+	}
+	return c.SourcePath + " is supplied by sources " + strings.Join(sources, ", ")
+}
+
+/*
+WithSourcePrecedence sets which source TransformMany uses when several
+supply the same path, the default is FirstSourceWins.  Different paths
+matching the same field compete through the matcher as usual, so the
+one with the best SimilarityRank wins.
+*/
+func WithSourcePrecedence(precedence SourcePrecedence) Option {
+	return func(o *options) {
+		o.precedence = precedence
+	}
+}
+
+/*
+WithConflictReporting makes TransformMany report a SourceConflict for every
+field mapped from a path several sources supplied different values for.
+The field is still mapped following the source precedence.
+*/
+func WithConflictReporting() Option {
+	return func(o *options) {
+		o.reportConflicts = true
+	}
+}
+
+/*
+TransformMany maps several sources into dst.  Each source is a struct,
+or a Source to look its paths up under a prefix.  Options such as
+WithConflictReporting are given to a Mapper, as in
+New(WithConflictReporting()).TransformMany; its member rules and
+plan cache only apply to Transform.
+*/
+func TransformMany(dst interface{}, srcs ...interface{}) error {
+	return defaultMapper.TransformMany(dst, srcs...)
+}
+
+/*
+TransformMany maps several sources into dst with the rules of the Mapper
+*/
+func (m *Mapper) TransformMany(dst interface{}, srcs ...interface{}) error {
+	if cannotModifyField(dst) {
+		return errors.New(dstError)
+	}
+	valueOfDst := findValueOf(dst)
+	if valueOfDst.Kind() != reflect.Struct {
+		return errors.New(unsupportedTransformation)
+	}
+
+	options := m.callOptions(nil, valueOfDst.Type(), nil)
+	options.conflicts = make(map[string][]int)
+	srcDescription := make(map[string]typeDescription)
+	suppliers := make(map[string]int)
+	declared := 0
+
+	for i, src := range srcs {
+		prefix := ""
+		if source, ok := src.(Source); ok {
+			prefix, src = source.Prefix, source.Value
+		}
+		if findValueOf(src).Kind() != reflect.Struct {
+			return errors.New(unsupportedTransformation)
+		}
+
+		description := describeStructure(src)
+		for path, val := range description {
+			path = appendFieldName(prefix, path)
+			val.Order += declared
+			existing, supplied := srcDescription[path]
+			if supplied && !sameValue(existing.FieldValue, val.FieldValue) {
+				if len(options.conflicts[path]) == 0 {
+					options.conflicts[path] = []int{suppliers[path]}
+				}
+				options.conflicts[path] = append(options.conflicts[path], i)
+			}
+			if !supplied || options.precedence == LastSourceWins {
+				srcDescription[path] = val
+				suppliers[path] = i
+			}
+		}
+		declared += len(description)
+	}
+
+	var errs FieldErrors
+	mapToRoot(dst, srcDescription, options, &errs)
+	return errs.orNil()
+}
+
+func (o *options) reportConflict(path, sourcePath string, errs *FieldErrors) {
+	if sources := o.conflicts[sourcePath]; o.reportConflicts && len(sources) != 0 {
+		errs.add(path, &SourceConflict{sourcePath, sources})
+	}
+}
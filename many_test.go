@@ -0,0 +1,75 @@
+package animagi_test
+
+import (
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type MergedInvoice struct {
+	Number   string
+	Total    int
+	Customer struct {
+		Name string
+		City string
+	}
+}
+
+var _ = Describe("TransformMany", func() {
+
+	header := struct {
+		Number string
+		Total  int
+	}{"INV-1", 100}
+	customer := struct{ Name, City string }{"Jane", "Springfield"}
+
+	It("Should merge the fields of every source", func() {
+		var dst MergedInvoice
+		err := animagi.TransformMany(&dst, header, animagi.Prefixed("Customer", customer))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Number).To(Equal("INV-1"))
+		Expect(dst.Total).To(Equal(100))
+		Expect(dst.Customer.Name).To(Equal("Jane"))
+		Expect(dst.Customer.City).To(Equal("Springfield"))
+	})
+
+	It("Should let the first source win by default", func() {
+		correction := struct{ Total int }{120}
+		var dst MergedInvoice
+		err := animagi.TransformMany(&dst, header, correction)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Total).To(Equal(100))
+	})
+
+	It("Should let the last source win when asked", func() {
+		correction := struct{ Total int }{120}
+		var dst MergedInvoice
+		mapper := animagi.New(animagi.WithSourcePrecedence(animagi.LastSourceWins))
+		err := mapper.TransformMany(&dst, header, correction)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Total).To(Equal(120))
+		Expect(dst.Number).To(Equal("INV-1"))
+	})
+
+	It("Should report conflicting sources", func() {
+		correction := struct{ Total int }{120}
+		same := struct{ Number string }{"INV-1"}
+		var dst MergedInvoice
+		mapper := animagi.New(animagi.WithConflictReporting())
+		err := mapper.TransformMany(&dst, header, same, correction)
+		Expect(err).To(HaveOccurred())
+		errs := err.(animagi.FieldErrors)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Path).To(Equal("Total"))
+		Expect(errs[0].Err).To(Equal(&animagi.SourceConflict{SourcePath: "Total", Sources: []int{0, 2}}))
+		Expect(dst.Total).To(Equal(100))
+	})
+
+	It("Should refuse sources that are not structs", func() {
+		var dst MergedInvoice
+		Expect(animagi.TransformMany(&dst, header, 5)).To(HaveOccurred())
+		Expect(animagi.TransformMany(&dst, header, animagi.WithConflictReporting())).To(HaveOccurred())
+		Expect(animagi.TransformMany(dst, header)).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,266 @@
+package animagi
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const unfitConverterResult = "converter result does not fit dst"
+
+var (
+	// ErrUnmapped is reported by strict mappers for destination fields no source matched
+	ErrUnmapped = errors.New("no source matched the field")
+	// ErrNilSource is reported under NilError for nil source pointers
+	ErrNilSource = errors.New("source is nil")
+)
+
+/*
+Mapper holds the rules fields are mapped with: the matcher and
+its similarity options, converters, nil policy and strictness.
+It caches the matches resolved between two types so repeated
+transformations only match once.  A Mapper is safe for concurrent
+use; the package level functions use a Mapper with default rules.
+*/
+type Mapper struct {
+	options  options
+	plans    sync.Map
+	typeMaps sync.Map
+}
+
+type planKey struct {
+	src reflect.Type
+	dst reflect.Type
+}
+
+var defaultMapper = New()
+
+/*
+New creates a Mapper with the default rules changed by opts
+*/
+func New(opts ...Option) *Mapper {
+	return &Mapper{options: *newOptions(opts)}
+}
+
+/*
+TransformWithChanges is Transform that also returns every
+destination field it modified along with the previous value
+*/
+func (m *Mapper) TransformWithChanges(src, dst interface{}, opts ...Option) (ChangeSet, error) {
+	changes := ChangeSet{}
+	err := m.Transform(src, dst, append(opts, recordChanges(&changes))...)
+	return changes, err
+}
+
+/*
+callOptions copies the rules of the mapper for a single call,
+reusing the plan of the two types unless opts change the matching.
+Without a srcType there is neither a plan nor member rules.
+*/
+func (m *Mapper) callOptions(srcType, dstType reflect.Type, opts []Option) *options {
+	o := m.options
+	if srcType != nil {
+		plan, _ := m.plans.LoadOrStore(planKey{srcType, dstType}, &sync.Map{})
+		o.plan = plan.(*sync.Map)
+		if typeMap, found := m.typeMaps.Load(planKey{srcType, dstType}); found {
+			o.members = typeMap.(*TypeMap).members
+		}
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+/*
+MatchFunc picks the source path a destination field is mapped from.
+names are all the paths the destination field can be reached by,
+qualified first, sources every described source path in sorted order
+and rank the SimilarityRank with the SimilarityOptions of the mapper.
+*/
+type MatchFunc func(names []string, sources []string, rank func(string, string) uint) (source string, sourceRank uint, found bool)
+
+/*
+ExactMatch only maps fields whose paths are the same,
+which is what Transform does by default
+*/
+func ExactMatch(names []string, sources []string, rank func(string, string) uint) (string, uint, bool) {
+	for _, name := range names {
+		if i := sort.SearchStrings(sources, name); i < len(sources) && sources[i] == name {
+			return name, 0, true
+		}
+	}
+	return "", MaxRank, false
+}
+
+/*
+FuzzyMatch maps fields to the source with the lowest SimilarityRank,
+as long as it is no more than maxRank.  Exact matches always win.
+*/
+func FuzzyMatch(maxRank uint) MatchFunc {
+	return func(names []string, sources []string, rank func(string, string) uint) (string, uint, bool) {
+		if source, _, found := ExactMatch(names, sources, rank); found {
+			return source, 0, true
+		}
+
+		best, bestRank := "", MaxRank
+		for _, name := range names {
+			for _, source := range sources {
+				if sourceRank := rank(name, source); sourceRank < bestRank {
+					best, bestRank = source, sourceRank
+				}
+			}
+		}
+		return best, bestRank, bestRank <= maxRank
+	}
+}
+
+/*
+WithMatcher sets how destination fields find their source
+*/
+func WithMatcher(matcher MatchFunc) Option {
+	return func(o *options) {
+		o.matcher = matcher
+		o.plan = nil
+	}
+}
+
+/*
+WithSimilarity sets the costs the matcher ranks paths with,
+DefaultSimilarityOptions unless given
+*/
+func WithSimilarity(similarity SimilarityOptions) Option {
+	return func(o *options) {
+		o.similarity = similarity
+		o.plan = nil
+	}
+}
+
+/*
+WithSimilarityWeights sets the cost of a depth difference, a missing
+letter and a wrong letter used while matching, keeping the rest of
+the similarity options
+*/
+func WithSimilarityWeights(depth, missingLetter, letter uint) Option {
+	return func(o *options) {
+		similarity := o.similarity
+		similarity.Depth, similarity.MissingLetter, similarity.Letter = depth, missingLetter, letter
+		WithSimilarity(similarity)(o)
+	}
+}
+
+/*
+WithConverter converts every value of srcType mapped onto a field
+of dstType with converter; its result must be assignable or
+convertible to dstType.  Pointers to srcType are converted too.
+*/
+func WithConverter(srcType, dstType reflect.Type, converter ConverterFunc) Option {
+	return func(o *options) {
+		converters := make(map[planKey]ConverterFunc, len(o.converters)+1)
+		for key, existing := range o.converters {
+			converters[key] = existing
+		}
+		converters[planKey{srcType, dstType}] = converter
+		o.converters = converters
+	}
+}
+
+/*
+NilPolicy decides what a nil source pointer does to its destination
+*/
+type NilPolicy int
+
+const (
+	// NilOverwrite sets the destination to its zero value
+	NilOverwrite NilPolicy = iota
+	// NilSkip leaves the destination untouched
+	NilSkip
+	// NilError leaves the destination untouched and reports ErrNilSource
+	NilError
+)
+
+/*
+WithNilPolicy sets how nil source pointers are handled, the default is NilOverwrite
+*/
+func WithNilPolicy(policy NilPolicy) Option {
+	return func(o *options) {
+		o.nilPolicy = policy
+	}
+}
+
+/*
+WithStrict reports ErrUnmapped for every settable destination field no source matched
+*/
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+/*
+findSource matches names against the source paths.  Which paths a
+source has depends on its nil pointers, so matches are cached per
+set of source paths: a nil struct pointer cannot hide a better
+match from later sources.
+*/
+func (o *options) findSource(names []string, srcDescription map[string]typeDescription) (sourceMatch, bool) {
+	if o.sources == nil {
+		o.sources = make([]string, 0, len(srcDescription))
+		for path := range srcDescription {
+			o.sources = append(o.sources, path)
+		}
+		sort.Strings(o.sources)
+		if o.plan != nil {
+			plan, _ := o.plan.LoadOrStore(strings.Join(o.sources, "\n"), &sync.Map{})
+			o.plan = plan.(*sync.Map)
+		}
+	}
+
+	if o.plan != nil {
+		if cached, ok := o.plan.Load(names[0]); ok {
+			match := cached.(sourceMatch)
+			match.typeDescription = srcDescription[match.Path]
+			return match, true
+		}
+	}
+
+	path, rank, found := o.matcher(names, o.sources, o.similarity.Rank)
+	if !found {
+		return sourceMatch{}, false
+	}
+	match := o.breakTie(names, sourceMatch{Path: path, Rank: rank}, srcDescription)
+	if o.plan != nil {
+		o.plan.Store(names[0], match)
+	}
+	match.typeDescription = srcDescription[match.Path]
+	return match, true
+}
+
+/*
+convert runs the converter registered for the types of src and dst
+*/
+func (o *options) convert(dst, src reflect.Value) (converted bool, err error) {
+	converter, found := o.converters[planKey{src.Type(), dst.Type()}]
+	if !found {
+		return false, nil
+	}
+
+	result, err := converter(valueInterface(src))
+	if err != nil {
+		return true, err
+	}
+	value := reflect.ValueOf(result)
+	switch {
+	case !value.IsValid():
+		dst.Set(reflect.Zero(dst.Type()))
+	case value.Type().AssignableTo(dst.Type()):
+		dst.Set(value)
+	case value.Type().ConvertibleTo(dst.Type()):
+		dst.Set(value.Convert(dst.Type()))
+	default:
+		return true, errors.New(unfitConverterResult)
+	}
+	return true, nil
+}
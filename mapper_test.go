@@ -0,0 +1,200 @@
+package animagi_test
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Celsius float64
+
+var _ = Describe("Mapper", func() {
+
+	Context("Matchers", func() {
+		src := struct {
+			FirstName string
+			Addres    string
+		}{"Jane", "Main St"}
+
+		type Destination struct {
+			FirstName string
+			Address   string
+		}
+
+		It("Should match exactly by default", func() {
+			var dst Destination
+			err := animagi.New().Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.FirstName).To(Equal("Jane"))
+			Expect(dst.Address).To(BeEmpty())
+		})
+
+		It("Should match similar names with a fuzzy matcher", func() {
+			mapper := animagi.New(animagi.WithMatcher(animagi.FuzzyMatch(5)))
+			var dst Destination
+			err := mapper.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Address).To(Equal("Main St"))
+
+			var again Destination
+			err = mapper.Transform(src, &again)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(again).To(Equal(dst))
+		})
+
+		It("Should rank with the similarity options of the mapper", func() {
+			similarity := animagi.DefaultSimilarityOptions
+			similarity.MissingLetter = 10
+			mapper := animagi.New(animagi.WithMatcher(animagi.FuzzyMatch(5)), animagi.WithSimilarity(similarity))
+			var dst Destination
+			err := mapper.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Address).To(BeEmpty())
+		})
+
+		It("Should rank with the weights of the mapper", func() {
+			mapper := animagi.New(animagi.WithMatcher(animagi.FuzzyMatch(5)), animagi.WithSimilarityWeights(3, 10, 1))
+			var dst Destination
+			err := mapper.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Address).To(BeEmpty())
+		})
+
+		It("Should match abbreviations with word tokens", func() {
+			similarity := animagi.DefaultSimilarityOptions
+			similarity.Tokenize = true
+			similarity.Dictionary = animagi.DefaultDictionary()
+			mapper := animagi.New(animagi.WithMatcher(animagi.FuzzyMatch(0)), animagi.WithSimilarity(similarity))
+
+			var dst struct {
+				Quantity    int
+				Description string
+				PostalCode  string
+			}
+			err := mapper.Transform(struct {
+				Qty  int
+				Desc string
+				Zip  string
+			}{3, "boxes", "12345"}, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Quantity).To(Equal(3))
+			Expect(dst.Description).To(Equal("boxes"))
+			Expect(dst.PostalCode).To(Equal("12345"))
+		})
+
+		It("Should not let per call options change the mapper", func() {
+			mapper := animagi.New()
+			var dst Destination
+			err := mapper.Transform(src, &dst, animagi.WithMatcher(animagi.FuzzyMatch(5)))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Address).To(Equal("Main St"))
+
+			var plain Destination
+			err = mapper.Transform(src, &plain)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plain.Address).To(BeEmpty())
+		})
+
+		It("Should not reuse matches made while a source pointer was nil", func() {
+			type Inner struct{ Name string }
+			type Source struct {
+				P   *Inner
+				Nam string
+			}
+			mapper := animagi.New(animagi.WithMatcher(animagi.FuzzyMatch(100)))
+
+			var dst struct{ Name string }
+			err := mapper.Transform(Source{nil, "x"}, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Name).To(Equal("x"))
+
+			err = mapper.Transform(Source{&Inner{"inner"}, "x"}, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Name).To(Equal("inner"))
+		})
+	})
+
+	Context("Converters", func() {
+		toFahrenheit := func(v interface{}) (interface{}, error) {
+			return strconv.FormatFloat(float64(v.(Celsius))*9/5+32, 'f', 1, 64) + "F", nil
+		}
+
+		It("Should convert between registered types", func() {
+			mapper := animagi.New(animagi.WithConverter(reflect.TypeOf(Celsius(0)), reflect.TypeOf(""), toFahrenheit))
+			src := struct{ Temp *Celsius }{new(Celsius)}
+			*src.Temp = 100
+			var dst struct{ Temp string }
+			err := mapper.Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Temp).To(Equal("212.0F"))
+		})
+
+		It("Should report converter errors", func() {
+			failing := func(v interface{}) (interface{}, error) { return nil, errors.New("too hot") }
+			mapper := animagi.New(animagi.WithConverter(reflect.TypeOf(Celsius(0)), reflect.TypeOf(""), failing))
+			var dst struct{ Temp string }
+			err := mapper.Transform(struct{ Temp Celsius }{100}, &dst)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("Temp"))
+		})
+	})
+
+	Context("Nil policy", func() {
+		src := struct{ Name *string }{nil}
+
+		It("Should zero the destination by default", func() {
+			dst := struct{ Name string }{"kept"}
+			err := animagi.New().Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Name).To(BeEmpty())
+		})
+
+		It("Should skip nil sources", func() {
+			dst := struct{ Name string }{"kept"}
+			err := animagi.New(animagi.WithNilPolicy(animagi.NilSkip)).Transform(src, &dst)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dst.Name).To(Equal("kept"))
+		})
+
+		It("Should report nil sources", func() {
+			dst := struct{ Name *string }{new(string)}
+			err := animagi.New(animagi.WithNilPolicy(animagi.NilError)).Transform(src, &dst)
+			Expect(err).To(HaveOccurred())
+			Expect(err.(animagi.FieldErrors)[0].Err).To(Equal(animagi.ErrNilSource))
+			Expect(dst.Name).NotTo(BeNil())
+		})
+
+		It("Should refuse a nil source pointer", func() {
+			var src *struct{ Name string }
+			var dst struct{ Name string }
+			err := animagi.New().Transform(src, &dst)
+			Expect(err).To(MatchError("could not transform to dst"))
+			_, err = animagi.Map[*struct{ Name string }, struct{ Name string }](src)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("Strictness", func() {
+		It("Should report unmapped destination fields", func() {
+			src := struct{ Name string }{"Jane"}
+			var dst struct {
+				Name    string
+				Age     int
+				Address Address
+			}
+			err := animagi.New(animagi.WithStrict()).Transform(src, &dst)
+			Expect(err).To(HaveOccurred())
+			errs := err.(animagi.FieldErrors)
+			Expect(errs).To(HaveLen(3))
+			Expect(errs[0].Path).To(Equal("Age"))
+			Expect(errs[0].Err).To(Equal(animagi.ErrUnmapped))
+			Expect(errs[2].Path).To(Equal("Address.City"))
+			Expect(dst.Name).To(Equal("Jane"))
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package animagi
+
+import (
+	"reflect"
+	"strings"
+)
+
+/*
+ComputeFunc computes the value of a destination field
+from the whole source value
+*/
+type ComputeFunc func(src interface{}) (interface{}, error)
+
+/*
+MemberRule states where a destination field gets its value from,
+overriding the matcher.  Create one with From, Ignore or Compute.
+*/
+type MemberRule struct {
+	from    string
+	ignore  bool
+	compute ComputeFunc
+}
+
+/*
+From takes the value of the destination field from srcPath.
+On a nested struct it redirects all of its fields, so
+ForMember("Shipping", From("Address")) maps Shipping.City from Address.City.
+*/
+func From(srcPath string) MemberRule {
+	return MemberRule{from: srcPath}
+}
+
+/*
+Ignore leaves the destination field, or every field of
+a nested struct, untouched
+*/
+func Ignore() MemberRule {
+	return MemberRule{ignore: true}
+}
+
+/*
+Compute sets the destination field to the result of compute
+called with the whole source value
+*/
+func Compute(compute ComputeFunc) MemberRule {
+	return MemberRule{compute: compute}
+}
+
+/*
+TypeMap holds the member rules used when mapping
+a source type onto a destination type
+*/
+type TypeMap struct {
+	members map[string]MemberRule
+}
+
+/*
+Configure returns the TypeMap for mapping values of the type of src
+onto the type of dst; either may be a value or a pointer.
+Configure a Mapper before using it concurrently.
+*/
+func (m *Mapper) Configure(src, dst interface{}) *TypeMap {
+	key := planKey{reflect.Indirect(reflect.ValueOf(src)).Type(), reflect.Indirect(reflect.ValueOf(dst)).Type()}
+	typeMap, _ := m.typeMaps.LoadOrStore(key, &TypeMap{members: make(map[string]MemberRule)})
+	return typeMap.(*TypeMap)
+}
+
+/*
+ForMember sets the rule of the destination field at dstPath
+*/
+func (t *TypeMap) ForMember(dstPath string, rule MemberRule) *TypeMap {
+	t.members[dstPath] = rule
+	return t
+}
+
+/*
+memberRule finds the rule of the destination field reached by names,
+or of the closest struct holding it.  The from path of the returned
+rule is rewritten to point at the field itself.
+*/
+func (o *options) memberRule(names []string) (MemberRule, bool) {
+	for _, name := range names {
+		for path := name; len(path) != 0; path = parentPath(path) {
+			rule, found := o.members[path]
+			if !found || (rule.compute != nil && path != name) {
+				continue
+			}
+			if len(rule.from) != 0 {
+				rule.from += name[len(path):]
+			}
+			return rule, true
+		}
+	}
+	return MemberRule{}, false
+}
+
+/*
+ruledSource resolves the source of a field that has a member rule
+*/
+func (o *options) ruledSource(rule MemberRule, srcDescription map[string]typeDescription) (sourceMatch, bool, error) {
+	if rule.compute == nil {
+		val, found := srcDescription[rule.from]
+		return sourceMatch{Path: rule.from, typeDescription: val}, found, nil
+	}
+
+	computed, err := rule.compute(valueInterface(findValueOf(o.src)))
+	if err != nil {
+		return sourceMatch{}, false, err
+	}
+	return sourceMatch{typeDescription: typeDescription{FieldType: reflect.TypeOf(computed), FieldValue: reflect.ValueOf(computed)}}, true, nil
+}
+
+func parentPath(path string) string {
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
@@ -0,0 +1,118 @@
+package animagi_test
+
+import (
+	"errors"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type GeneratedAddress struct {
+	Town   string
+	Street string
+}
+
+type GeneratedOrder struct {
+	First   string
+	Last    string
+	Address GeneratedAddress
+	Notes   string
+}
+
+type OrderView struct {
+	FullName string
+	Shipping struct {
+		City   string
+		Street string
+	}
+	Billing       *GeneratedAddress
+	Notes         string
+	InternalNotes string
+}
+
+var _ = Describe("Member rules", func() {
+
+	src := GeneratedOrder{"Jane", "Doe", GeneratedAddress{"Springfield", "Main St"}, "fragile"}
+
+	It("Should take a field from another source path", func() {
+		mapper := animagi.New()
+		mapper.Configure(GeneratedOrder{}, OrderView{}).
+			ForMember("Shipping.City", animagi.From("Address.Town"))
+
+		var dst OrderView
+		err := mapper.Transform(src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Shipping.City).To(Equal("Springfield"))
+		Expect(dst.Shipping.Street).To(BeEmpty())
+	})
+
+	It("Should redirect all fields of a nested struct", func() {
+		mapper := animagi.New()
+		mapper.Configure(&GeneratedOrder{}, &OrderView{}).
+			ForMember("Shipping", animagi.From("Address")).
+			ForMember("Shipping.City", animagi.From("Address.Town")).
+			ForMember("Billing", animagi.From("Address"))
+
+		var dst OrderView
+		err := mapper.Transform(&src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Shipping.City).To(Equal("Springfield"))
+		Expect(dst.Shipping.Street).To(Equal("Main St"))
+		Expect(dst.Billing).To(Equal(&src.Address))
+	})
+
+	It("Should ignore fields", func() {
+		mapper := animagi.New(animagi.WithStrict())
+		mapper.Configure(GeneratedOrder{}, OrderView{}).
+			ForMember("Notes", animagi.Ignore()).
+			ForMember("InternalNotes", animagi.Ignore()).
+			ForMember("FullName", animagi.Ignore()).
+			ForMember("Shipping", animagi.Ignore()).
+			ForMember("Billing", animagi.Ignore())
+
+		dst := OrderView{Notes: "kept"}
+		err := mapper.Transform(src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Notes).To(Equal("kept"))
+	})
+
+	It("Should compute fields from the whole source", func() {
+		mapper := animagi.New()
+		mapper.Configure(GeneratedOrder{}, OrderView{}).
+			ForMember("FullName", animagi.Compute(func(src interface{}) (interface{}, error) {
+				order := src.(GeneratedOrder)
+				return order.First + " " + order.Last, nil
+			}))
+
+		var dst OrderView
+		err := mapper.Transform(&src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.FullName).To(Equal("Jane Doe"))
+		Expect(dst.Notes).To(Equal("fragile"))
+	})
+
+	It("Should report compute errors", func() {
+		mapper := animagi.New()
+		mapper.Configure(GeneratedOrder{}, OrderView{}).
+			ForMember("FullName", animagi.Compute(func(src interface{}) (interface{}, error) {
+				return nil, errors.New("no name")
+			}))
+
+		var dst OrderView
+		err := mapper.Transform(src, &dst)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("FullName"))
+	})
+
+	It("Should only apply to the configured types", func() {
+		mapper := animagi.New()
+		mapper.Configure(GeneratedOrder{}, OrderView{}).ForMember("Notes", animagi.Ignore())
+
+		var dst struct{ Notes string }
+		err := mapper.Transform(src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Notes).To(Equal("fragile"))
+	})
+})
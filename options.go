@@ -0,0 +1,96 @@
+package animagi
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+type options struct {
+	matcher          MatchFunc
+	similarity       SimilarityOptions
+	converters       map[planKey]ConverterFunc
+	members          map[string]MemberRule
+	enums            map[reflect.Type]*enum
+	nilPolicy        NilPolicy
+	strict           bool
+	strictMatching   bool
+	conversionPolicy ConversionPolicy
+	runeConversion   bool
+	skipZeroSource   bool
+	onlyZeroDst      bool
+	changes          *ChangeSet
+	beforeHooks      []BeforeFieldFunc
+	afterHooks       []AfterFieldFunc
+	validation       ValidationMode
+	precedence       SourcePrecedence
+	reportConflicts  bool
+	deepCopy         bool
+	stringer         bool
+	timeLayout       string
+	timeZone         *time.Location
+
+	// state of a single call
+	src              interface{}
+	plan             *sync.Map
+	sources          []string
+	conflicts        map[string][]int
+	validationFailed bool
+}
+
+/*
+Option configures a Mapper, or a single call to Transform
+*/
+type Option func(*options)
+
+/*
+WithConversionPolicy sets how numeric conversions that
+would overflow, lose their sign or drop a fraction are handled.
+The default is ConversionAllow.
+*/
+func WithConversionPolicy(policy ConversionPolicy) Option {
+	return func(o *options) {
+		o.conversionPolicy = policy
+	}
+}
+
+/*
+WithRuneConversion restores Go's conversion of integers
+to strings, where 65 becomes "A".  Without it integers
+mapped onto strings are formatted as decimals.
+*/
+func WithRuneConversion() Option {
+	return func(o *options) {
+		o.runeConversion = true
+	}
+}
+
+/*
+WithSkipZeroSource turns Transform into a merge: source fields
+holding their zero value, or a nil pointer, leave the matching
+destination field as it is.  A non-nil pointer to a zero value
+is still copied, so PATCH requests can set fields to zero.
+*/
+func WithSkipZeroSource() Option {
+	return func(o *options) {
+		o.skipZeroSource = true
+	}
+}
+
+/*
+WithOverwriteOnlyZero only sets destination fields that still
+hold their zero value, keeping anything already filled in
+*/
+func WithOverwriteOnlyZero() Option {
+	return func(o *options) {
+		o.onlyZeroDst = true
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{matcher: ExactMatch, similarity: DefaultSimilarityOptions, conversionPolicy: ConversionAllow}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
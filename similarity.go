@@ -3,6 +3,7 @@ package animagi
 import (
 	"errors"
 	"strings"
+	"unicode"
 )
 
 const (
@@ -17,105 +18,223 @@ const (
 	MaxRank = ^uint(0)
 )
 
+/*
+SimilarityOptions are the costs used to rank two strings:
+Depth for every level one path is deeper than the other,
+MissingLetter for every letter one name is longer than the other
+and Letter for every letter that differs.  Letters are runes;
+FoldCase compares them under Unicode case folding and Normalize,
+such as norm.NFC.String, is applied to both strings first.
+
+With Tokenize every name is also ranked as a sequence of lower case
+words, as ShippingAddr is shipping addr, after replacing the words
+found in Dictionary, so Addr and Address both read address.
+A name keeps the better of its letter and word rank.
+*/
+type SimilarityOptions struct {
+	Depth         uint
+	MissingLetter uint
+	Letter        uint
+	FoldCase      bool
+	Normalize     func(string) string
+	Tokenize      bool
+	Dictionary    map[string]string
+}
+
+/*
+DefaultSimilarityOptions are the costs SimilarityRank uses
+*/
+var DefaultSimilarityOptions = SimilarityOptions{Depth: dFactor, MissingLetter: mlFactor, Letter: lFactor}
+
 /*
 SimilarityRank computes the similarity between two strings
 Some presumptions of the strings are to be considered:
- - a '.' denotes a depth increase
- - a string consistenting of only '.' will have MaximumRank
- - a letter is considered missing if one string is longer than the other
+  - a '.' denotes a depth increase
+  - a string consistenting of only '.' will have MaximumRank
+  - a letter is considered missing if one string is longer than the other
 */
 func SimilarityRank(str1, str2 string) (rank uint) {
+	return DefaultSimilarityOptions.Rank(str1, str2)
+}
 
-	if err := validateString(str1); err != nil {
+/*
+Rank is SimilarityRank with the costs of similarity.
+Ranks saturate at MaxRank instead of wrapping around.
+*/
+func (similarity SimilarityOptions) Rank(str1, str2 string) (rank uint) {
+	segments, valid := similarity.segments(str1, str2)
+	if !valid {
 		return MaxRank
 	}
+	for _, segment := range segments {
+		rank = addRank(rank, segment.Rank)
+	}
+	return rank
+}
+
+/*
+segments pairs the segments of two paths the way they are ranked:
+the last ones together, and the others in order, leaving out the
+segments of the deeper path that match worst at a cost of Depth each
+*/
+func (similarity SimilarityOptions) segments(str1, str2 string) (segments []SegmentRank, valid bool) {
+	if similarity.Normalize != nil {
+		str1, str2 = similarity.Normalize(str1), similarity.Normalize(str2)
+	}
+
+	if err := validateString(str1); err != nil {
+		return nil, false
+	}
 
 	if err := validateString(str2); err != nil {
-		return MaxRank
+		return nil, false
 	}
 
 	str1Depths := strings.Split(str1, ".")
 	str2Depths := strings.Split(str2, ".")
 
-	mostSimilarMatch := stringSimilarityRank(str1Depths[len(str1Depths)-1], str2Depths[len(str2Depths)-1])
-
-	var longerPath, shorterPath []string
-
-	if len(str1Depths) > len(str2Depths) {
-		longerPath = str1Depths
-		shorterPath = str2Depths
-	} else {
-		longerPath = str2Depths
-		shorterPath = str1Depths
+	longerPath, shorterPath, swapped := str1Depths, str2Depths, false
+	if len(str1Depths) < len(str2Depths) {
+		longerPath, shorterPath, swapped = str2Depths, str1Depths, true
 	}
 
-	differenceInPaths := len(longerPath) - len(shorterPath)
-
-	mostSimilarMatch += uint(dFactor * differenceInPaths)
-	mostSimilarMatch += mostSimilarSubPaths(append(longerPath[0:len(longerPath)-1]), append(shorterPath[0:len(shorterPath)-1]), differenceInPaths)
+	kept := similarity.mostSimilarSubPaths(longerPath[:len(longerPath)-1], shorterPath[:len(shorterPath)-1])
+	next := 0
+	for i, segment := range longerPath[:len(longerPath)-1] {
+		if next < len(kept) && kept[next] == i {
+			segments = append(segments, pairSegments(segment, shorterPath[next], similarity.stringSimilarityRank(segment, shorterPath[next]), swapped))
+			next++
+		} else {
+			segments = append(segments, pairSegments(segment, "", similarity.Depth, swapped))
+		}
+	}
 
-	return mostSimilarMatch
+	last1, last2 := longerPath[len(longerPath)-1], shorterPath[len(shorterPath)-1]
+	segments = append(segments, pairSegments(last1, last2, similarity.stringSimilarityRank(last1, last2), swapped))
+	return segments, true
 }
 
-func mostSimilarSubPaths(longerPath, shorterPath []string, differenceInPaths int) (rank uint) {
-	rank = MaxRank
-
-	if len(shorterPath) == 0 {
-		return 0
+func pairSegments(longer, shorter string, rank uint, swapped bool) SegmentRank {
+	if swapped {
+		return SegmentRank{shorter, longer, rank}
 	}
+	return SegmentRank{longer, shorter, rank}
+}
 
-	if differenceInPaths == 0 {
-		rank = 0
-		for i := 0; i < len(longerPath); i++ {
-			rank += stringSimilarityRank(longerPath[i], shorterPath[i])
+/*
+mostSimilarSubPaths picks the segments of longerPath, in order, to pair
+with every segment of shorterPath so the sum of their ranks is lowest
+*/
+func (similarity SimilarityOptions) mostSimilarSubPaths(longerPath, shorterPath []string) (kept []int) {
+	// best[i][j] is the lowest rank pairing the last j segments of
+	// shorterPath with segments from the last i of longerPath
+	best := make([][]uint, len(longerPath)+1)
+	for i := range best {
+		best[i] = make([]uint, len(shorterPath)+1)
+		for j := 1; j <= len(shorterPath); j++ {
+			best[i][j] = MaxRank
 		}
-	} else {
-		for i := 0; i < len(longerPath); i++ {
-			adjusted := make([]string, len(longerPath))
-			copy(adjusted, longerPath)
-			subRank := mostSimilarSubPaths(append(adjusted[:i], adjusted[i+1:]...), shorterPath, differenceInPaths-1)
-			adjusted = nil
-			if subRank < rank {
-				rank = subRank
+	}
+	for i := 1; i <= len(longerPath); i++ {
+		for j := 1; j <= len(shorterPath) && j <= i; j++ {
+			paired := addRank(best[i-1][j-1], similarity.stringSimilarityRank(longerPath[len(longerPath)-i], shorterPath[len(shorterPath)-j]))
+			best[i][j] = paired
+			if skipped := best[i-1][j]; skipped < paired {
+				best[i][j] = skipped
 			}
+		}
+	}
 
-			if rank == uint(dFactor*(differenceInPaths-1)) {
-				break
-			}
+	for i, j := len(longerPath), len(shorterPath); j > 0; i-- {
+		if best[i][j] != best[i-1][j] || i == j {
+			kept = append(kept, len(longerPath)-i)
+			j--
 		}
 	}
+	return kept
+}
 
+func (similarity SimilarityOptions) stringSimilarityRank(str1, str2 string) (rank uint) {
+	rank = similarity.letterRank(str1, str2)
+	if similarity.Tokenize {
+		if words := similarity.wordRank(str1, str2); words < rank {
+			rank = words
+		}
+	}
 	return rank
 }
 
-func stringSimilarityRank(str1, str2 string) (rank uint) {
-	str1Len := len(str1)
-	str2Len := len(str2)
+func (similarity SimilarityOptions) letterRank(str1, str2 string) (rank uint) {
+	runes1 := []rune(str1)
+	runes2 := []rune(str2)
+	str1Len := len(runes1)
+	str2Len := len(runes2)
 	shorterLen := str1Len
 
 	if str1Len == 0 {
-		return uint(mlFactor * str2Len)
+		return mulRank(similarity.MissingLetter, str2Len)
 	} else if str2Len == 0 {
-		return uint(mlFactor * str1Len)
+		return mulRank(similarity.MissingLetter, str1Len)
 	}
 
 	shorterLen = str1Len
 
 	if str1Len < str2Len {
-		rank = uint(mlFactor * (str2Len - str1Len))
+		rank = mulRank(similarity.MissingLetter, str2Len-str1Len)
 	} else if str2Len < str1Len {
 		shorterLen = str2Len
-		rank = uint(mlFactor * (str1Len - str2Len))
+		rank = mulRank(similarity.MissingLetter, str1Len-str2Len)
 	}
 
 	for i := 0; i < shorterLen; i++ {
-		if str1[i] != str2[i] {
-			rank += lFactor
+		if !similarity.sameLetter(runes1[i], runes2[i]) {
+			rank = addRank(rank, similarity.Letter)
 		}
 	}
 	return rank
 }
 
+/*
+sameLetter compares two runes, under simple case folding when asked
+*/
+func (similarity SimilarityOptions) sameLetter(r1, r2 rune) bool {
+	if r1 == r2 {
+		return true
+	}
+	if !similarity.FoldCase {
+		return false
+	}
+	for folded := unicode.SimpleFold(r1); folded != r1; folded = unicode.SimpleFold(folded) {
+		if folded == r2 {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+addRank adds two ranks, saturating at MaxRank
+*/
+func addRank(a, b uint) uint {
+	if a > MaxRank-b {
+		return MaxRank
+	}
+	return a + b
+}
+
+/*
+mulRank multiplies a cost by a count, saturating at MaxRank
+*/
+func mulRank(cost uint, count int) uint {
+	if count <= 0 {
+		return 0
+	}
+	if cost != 0 && uint(count) > MaxRank/cost {
+		return MaxRank
+	}
+	return cost * uint(count)
+}
+
 func validateString(str string) (err error) {
 	if str == "." || str == " " {
 		err = errors.New(invalidString)
@@ -1,6 +1,8 @@
 package animagi_test
 
 import (
+	"strings"
+
 	"github.com/barreeyentos/animagi"
 
 	. "github.com/onsi/ginkgo"
@@ -126,5 +128,88 @@ var _ = Describe("Animagi", func() {
 			rank := animagi.SimilarityRank("user.employer.manegers.details.name", "manager.name")
 			Expect(rank).To(BeNumerically("==", 3*dFactor+1*lFactor+1*mlFactor))
 		})
+
+		It("Should align every segment of the shorter path", func() {
+			Expect(animagi.SimilarityRank("usr.ab", "usr.user.x.bc.ab")).To(BeNumerically("==", 3*dFactor))
+			Expect(animagi.SimilarityRank("usr.user.x.bc.ab", "usr.ab")).To(BeNumerically("==", 3*dFactor))
+			Expect(animagi.SimilarityRank("a.usr.ab", "a.b.usr.user.x.bc.ab")).To(BeNumerically("==", 4*dFactor))
+		})
+	})
+
+	Context("Similarity options", func() {
+		It("Should rank with the costs given", func() {
+			cheapDepth := animagi.SimilarityOptions{Depth: 1, MissingLetter: 10, Letter: 4}
+			Expect(cheapDepth.Rank("user.employer.name", "name")).To(BeNumerically("==", 2))
+			Expect(cheapDepth.Rank("name", "nome")).To(BeNumerically("==", 4))
+			Expect(cheapDepth.Rank("names", "name")).To(BeNumerically("==", 10))
+		})
+
+		It("Should rank like SimilarityRank by default", func() {
+			rank := animagi.DefaultSimilarityOptions.Rank("user.employer.maneger.details.name", "manager.name")
+			Expect(rank).To(Equal(animagi.SimilarityRank("user.employer.maneger.details.name", "manager.name")))
+		})
+
+		It("Should saturate at MaxRank instead of wrapping around", func() {
+			huge := animagi.SimilarityOptions{Depth: animagi.MaxRank / 2, MissingLetter: animagi.MaxRank / 3, Letter: animagi.MaxRank}
+			Expect(huge.Rank("a.b.c.name", "name")).To(Equal(animagi.MaxRank))
+			Expect(huge.Rank("name", "namesake")).To(Equal(animagi.MaxRank))
+			Expect(huge.Rank("user.name", "usar.nome")).To(Equal(animagi.MaxRank))
+			Expect(huge.Rank("user.name", "user.name")).To(BeZero())
+		})
+	})
+
+	Context("Unicode names", func() {
+		It("Should count runes instead of bytes", func() {
+			Expect(animagi.SimilarityRank("Größe", "Grösse")).To(BeNumerically("==", 2*lFactor+1*mlFactor))
+			Expect(animagi.SimilarityRank("名前", "名称")).To(BeNumerically("==", 1*lFactor))
+			Expect(animagi.SimilarityRank("user.名前", "名前")).To(BeNumerically("==", 1*dFactor))
+		})
+
+		It("Should only fold case when asked", func() {
+			folding := animagi.DefaultSimilarityOptions
+			folding.FoldCase = true
+			Expect(animagi.SimilarityRank("user.ÉTAT", "User.état")).To(BeNumerically("==", 5*lFactor))
+			Expect(folding.Rank("user.ÉTAT", "User.état")).To(BeZero())
+			Expect(folding.Rank("Kelvin", "\u212Aelvin")).To(BeZero())
+		})
+
+		It("Should normalize names when asked", func() {
+			normalizing := animagi.DefaultSimilarityOptions
+			normalizing.Normalize = strings.NewReplacer("e\u0301", "é").Replace
+			Expect(animagi.SimilarityRank("café", "cafe\u0301")).To(BeNumerically("==", 1*lFactor+1*mlFactor))
+			Expect(normalizing.Rank("café", "cafe\u0301")).To(BeZero())
+		})
+	})
+
+	Context("Word tokens", func() {
+		tokens := animagi.DefaultSimilarityOptions
+		tokens.Tokenize = true
+		tokens.Dictionary = animagi.DefaultDictionary()
+
+		It("Should expand abbreviations and synonyms", func() {
+			Expect(tokens.Rank("Addr", "Address")).To(BeZero())
+			Expect(tokens.Rank("Qty", "Quantity")).To(BeZero())
+			Expect(tokens.Rank("Desc", "Description")).To(BeZero())
+			Expect(tokens.Rank("Zip", "PostalCode")).To(BeZero())
+			Expect(tokens.Rank("order.ShippingAddr", "order.shipping_address")).To(BeZero())
+		})
+
+		It("Should rank missing and changed words", func() {
+			Expect(tokens.Rank("CustomerFirstName", "FirstName")).To(BeNumerically("==", 8*mlFactor))
+			Expect(tokens.Rank("HTTPServerName", "HttpServerNeme")).To(BeNumerically("==", 1*lFactor))
+			Expect(tokens.Rank("Address2", "Addr1")).To(BeNumerically("==", 1*lFactor))
+		})
+
+		It("Should keep the letter rank when it is better", func() {
+			Expect(tokens.Rank("Firstname", "FirstName")).To(BeNumerically("==", 1*lFactor))
+			Expect(tokens.Rank("Nme", "Name")).To(Equal(animagi.SimilarityRank("Nme", "Name")))
+		})
+
+		It("Should only use the dictionary given", func() {
+			plain := tokens
+			plain.Dictionary = map[string]string{"sku": "stock keeping unit"}
+			Expect(plain.Rank("Qty", "Quantity")).To(Equal(animagi.SimilarityRank("Qty", "Quantity")))
+			Expect(plain.Rank("SKU", "StockKeepingUnit")).To(BeZero())
+		})
 	})
 })
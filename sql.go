@@ -0,0 +1,80 @@
+package animagi
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+)
+
+var (
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+)
+
+/*
+isSQLValue tells if t is a database value such as sql.NullString,
+implementing driver.Valuer or sql.Scanner
+*/
+func isSQLValue(t reflect.Type) bool {
+	return implements(t, valuerType) || reflect.PtrTo(t).Implements(scannerType)
+}
+
+/*
+unwrapSource replaces src with the value it would be stored as when
+it is a driver.Valuer mapped onto another type without a converter,
+so a NULL sql.NullString becomes an invalid value like a nil pointer
+*/
+func (o *options) unwrapSource(src reflect.Value, dstType reflect.Type) (reflect.Value, error) {
+	value := reflect.Indirect(src)
+	if dstType.Kind() == reflect.Ptr {
+		dstType = dstType.Elem()
+	}
+	if !value.IsValid() || value.Type() == dstType {
+		return src, nil
+	}
+	if _, found := o.converters[planKey{value.Type(), dstType}]; found {
+		return src, nil
+	}
+	if stored, isValuer, err := driverValue(value); isValuer {
+		return stored, err
+	}
+	return src, nil
+}
+
+/*
+driverValue calls Value on v when it is a driver.Valuer
+*/
+func driverValue(v reflect.Value) (stored reflect.Value, isValuer bool, err error) {
+	valuer, ok := interfaceOf(v, valuerType)
+	if !ok {
+		return v, false, nil
+	}
+	value, err := valuer.(driver.Valuer).Value()
+	if err != nil {
+		return v, true, err
+	}
+	return reflect.ValueOf(value), true, nil
+}
+
+/*
+isNull tells if src is a nil pointer or a NULL database value
+*/
+func isNull(src reflect.Value) bool {
+	stored, _, err := driverValue(reflect.Indirect(src))
+	return err == nil && !stored.IsValid()
+}
+
+/*
+scanInto fills dst from src when dst is a sql.Scanner, such as
+sql.NullInt64, and reports whether it did
+*/
+func scanInto(dst, src reflect.Value) (scanned bool, err error) {
+	if !dst.CanAddr() || !dst.Addr().Type().Implements(scannerType) {
+		return false, nil
+	}
+	value := valueInterface(src)
+	if converted, err := driver.DefaultParameterConverter.ConvertValue(value); err == nil {
+		value = converted
+	}
+	return true, dst.Addr().Interface().(sql.Scanner).Scan(value)
+}
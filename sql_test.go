@@ -0,0 +1,144 @@
+package animagi_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type UserRow struct {
+	Name      sql.NullString
+	Age       sql.NullInt64
+	CreatedAt sql.NullTime
+	Nickname  sql.NullString
+}
+
+type UserDTO struct {
+	Name      *string
+	Age       int64
+	CreatedAt time.Time
+	Nickname  string
+}
+
+type Cents int64
+
+func (c Cents) Value() (driver.Value, error) {
+	if c < 0 {
+		return nil, errors.New("negative amount")
+	}
+	return int64(c), nil
+}
+
+type JSONAddress struct{ Street string }
+
+func (a JSONAddress) Value() (driver.Value, error) {
+	return `{"Street":"` + a.Street + `"}`, nil
+}
+
+func (a *JSONAddress) Scan(src interface{}) error {
+	return errors.New("not implemented")
+}
+
+type AddressDTO struct{ Street string }
+
+var _ = Describe("Database values", func() {
+
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	It("Should unwrap Null types", func() {
+		row := UserRow{
+			Name:      sql.NullString{String: "Jane", Valid: true},
+			Age:       sql.NullInt64{Int64: 42, Valid: true},
+			CreatedAt: sql.NullTime{Time: created, Valid: true},
+		}
+		var dto UserDTO
+		err := animagi.Transform(row, &dto)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*dto.Name).To(Equal("Jane"))
+		Expect(dto.Age).To(Equal(int64(42)))
+		Expect(dto.CreatedAt).To(Equal(created))
+		Expect(dto.Nickname).To(BeEmpty())
+	})
+
+	It("Should map NULL like a nil pointer", func() {
+		dto := UserDTO{Name: new(string), Age: 7, Nickname: "kept"}
+		err := animagi.Transform(UserRow{}, &dto, animagi.WithNilPolicy(animagi.NilSkip))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dto.Name).NotTo(BeNil())
+		Expect(dto.Nickname).To(Equal("kept"))
+
+		err = animagi.Transform(UserRow{}, &dto)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dto.Name).To(BeNil())
+		Expect(dto.Age).To(BeZero())
+	})
+
+	It("Should wrap values into Null types", func() {
+		name := "Jane"
+		var row UserRow
+		err := animagi.Transform(UserDTO{Name: &name, Age: 42, CreatedAt: created}, &row)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(row.Name).To(Equal(sql.NullString{String: "Jane", Valid: true}))
+		Expect(row.Age).To(Equal(sql.NullInt64{Int64: 42, Valid: true}))
+		Expect(row.CreatedAt).To(Equal(sql.NullTime{Time: created, Valid: true}))
+		Expect(row.Nickname).To(Equal(sql.NullString{Valid: true}))
+	})
+
+	It("Should wrap nil pointers as NULL", func() {
+		row := UserRow{Name: sql.NullString{String: "Jane", Valid: true}}
+		err := animagi.Transform(UserDTO{}, &row)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(row.Name.Valid).To(BeFalse())
+	})
+
+	It("Should convert between Null types", func() {
+		src := struct{ Age sql.NullInt32 }{sql.NullInt32{Int32: 42, Valid: true}}
+		var dst struct{ Age sql.NullInt64 }
+		err := animagi.Transform(src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Age).To(Equal(sql.NullInt64{Int64: 42, Valid: true}))
+	})
+
+	It("Should use any driver.Valuer and report its errors", func() {
+		var dst struct{ Price, Tax int }
+		err := animagi.Transform(struct{ Price, Tax Cents }{250, -1}, &dst)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("Tax"))
+		Expect(dst.Price).To(Equal(250))
+	})
+
+	It("Should report values that cannot be scanned", func() {
+		var row struct{ Age sql.NullInt64 }
+		err := animagi.Transform(struct{ Age string }{"old"}, &row)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("Age"))
+	})
+
+	It("Should map database values field by field onto plain structs", func() {
+		var dst struct{ Home AddressDTO }
+		err := animagi.Transform(struct{ Home JSONAddress }{JSONAddress{"Main"}}, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Home.Street).To(Equal("Main"))
+
+		var back struct{ Home *JSONAddress }
+		err = animagi.Transform(dst, &back)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(back.Home).To(Equal(&JSONAddress{"Main"}))
+
+		var stored struct{ Home string }
+		err = animagi.Transform(struct{ Home JSONAddress }{JSONAddress{"Main"}}, &stored)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(stored.Home).To(Equal(`{"Street":"Main"}`))
+
+		report := animagi.CanTransform(reflect.TypeOf(struct{ Home JSONAddress }{}), reflect.TypeOf(dst))
+		Expect(report.DstCoverage).To(BeNumerically("==", 100))
+		Expect(report.SrcCoverage).To(BeNumerically("==", 100))
+	})
+})
@@ -0,0 +1,99 @@
+package animagi
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+var (
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	stringerType        = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+/*
+WithStringer formats values implementing fmt.Stringer with
+String when they are mapped onto a string
+*/
+func WithStringer() Option {
+	return func(o *options) {
+		o.stringer = true
+	}
+}
+
+/*
+isTextValue tells if t has a text form, as an IP or a UUID does
+*/
+func isTextValue(t reflect.Type) bool {
+	return implements(t, textMarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PtrTo(t).Implements(iface)
+}
+
+/*
+interfaceOf returns v as iface, calling methods with
+pointer receivers on an addressable copy when needed
+*/
+func interfaceOf(v reflect.Value, iface reflect.Type) (interface{}, bool) {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil, false
+	}
+	if v.Type().Implements(iface) {
+		return v.Interface(), true
+	}
+	if !reflect.PtrTo(v.Type()).Implements(iface) {
+		return nil, false
+	}
+	if !v.CanAddr() {
+		copied := reflect.New(v.Type())
+		copied.Elem().Set(v)
+		return copied.Interface(), true
+	}
+	return v.Addr().Interface(), true
+}
+
+/*
+textOf returns the text form of src, from MarshalText or,
+when asked for with WithStringer, from String
+*/
+func (o *options) textOf(src reflect.Value) (text string, hasText bool, err error) {
+	if marshaler, ok := interfaceOf(src, textMarshalerType); ok {
+		bytes, err := marshaler.(encoding.TextMarshaler).MarshalText()
+		return string(bytes), true, err
+	}
+	if stringer, ok := interfaceOf(src, stringerType); ok && o.stringer {
+		return stringer.(fmt.Stringer).String(), true, nil
+	}
+	return "", false, nil
+}
+
+/*
+convertText moves src into dst through text: strings and text forms
+are unmarshaled into an encoding.TextUnmarshaler, and text forms are
+set on strings.  It reports whether it handled the field.
+*/
+func (o *options) convertText(dst, src reflect.Value) (converted bool, err error) {
+	unmarshaler := dst.CanAddr() && dst.Addr().Type().Implements(textUnmarshalerType)
+	if !unmarshaler && dst.Kind() != reflect.String {
+		return false, nil
+	}
+
+	text, hasText, err := o.textOf(src)
+	if !hasText && unmarshaler && src.Kind() == reflect.String {
+		text, hasText = src.String(), true
+	}
+	switch {
+	case !hasText:
+		return false, nil
+	case err != nil:
+		return true, err
+	case unmarshaler:
+		return true, dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	default:
+		dst.SetString(text)
+		return true, nil
+	}
+}
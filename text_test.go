@@ -0,0 +1,108 @@
+package animagi_test
+
+import (
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type AccountID struct {
+	Region string
+	Number string
+}
+
+func (id AccountID) MarshalText() ([]byte, error) {
+	return []byte(id.Region + "-" + id.Number), nil
+}
+
+func (id *AccountID) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), "-", 2)
+	if len(parts) != 2 {
+		return errors.New("malformed account id")
+	}
+	id.Region, id.Number = parts[0], parts[1]
+	return nil
+}
+
+type Color int
+
+func (c Color) String() string {
+	return [...]string{"red", "green"}[c]
+}
+
+type Server struct {
+	ID      AccountID
+	Address net.IP
+	Owner   *AccountID
+}
+
+type ServerDTO struct {
+	ID      string
+	Address string
+	Owner   string
+}
+
+var _ = Describe("Text conversions", func() {
+
+	It("Should marshal values onto strings", func() {
+		server := Server{AccountID{"eu", "42"}, net.IPv4(10, 0, 0, 1), &AccountID{"us", "7"}}
+		var dto ServerDTO
+		err := animagi.Transform(server, &dto)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dto).To(Equal(ServerDTO{"eu-42", "10.0.0.1", "us-7"}))
+	})
+
+	It("Should unmarshal strings into values", func() {
+		var server Server
+		err := animagi.Transform(ServerDTO{"eu-42", "10.0.0.1", "us-7"}, &server)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.ID).To(Equal(AccountID{"eu", "42"}))
+		Expect(server.Address.Equal(net.IPv4(10, 0, 0, 1))).To(BeTrue())
+		Expect(*server.Owner).To(Equal(AccountID{"us", "7"}))
+	})
+
+	It("Should report values that do not unmarshal", func() {
+		var server Server
+		err := animagi.Transform(ServerDTO{"broken", "10.0.0.1", "us-7"}, &server)
+		Expect(err).To(HaveOccurred())
+		errs := err.(animagi.FieldErrors)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Path).To(Equal("ID"))
+		Expect(server.Owner).NotTo(BeNil())
+	})
+
+	It("Should only use String when asked", func() {
+		src := struct{ Color Color }{1}
+		var dst struct{ Color string }
+		err := animagi.Transform(src, &dst, animagi.WithStringer())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Color).To(Equal("green"))
+
+		err = animagi.Transform(src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Color).To(Equal("1"))
+	})
+
+	It("Should map text values field by field onto and from plain structs", func() {
+		src := struct {
+			ID struct{ Region, Number string }
+		}{}
+		src.ID.Region, src.ID.Number = "eu", "42"
+		var server Server
+		err := animagi.Transform(src, &server)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.ID).To(Equal(AccountID{"eu", "42"}))
+
+		var back struct {
+			ID struct{ Region, Number string }
+		}
+		err = animagi.Transform(server, &back)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(back).To(Equal(src))
+	})
+})
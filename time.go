@@ -0,0 +1,140 @@
+package animagi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+/*
+WithTimeLayout sets the layout times are formatted into and
+parsed from when mapped onto strings, time.RFC3339 by default
+*/
+func WithTimeLayout(layout string) Option {
+	return func(o *options) {
+		o.timeLayout = layout
+	}
+}
+
+/*
+WithTimeZone sets the location times are formatted in, strings
+without a zone are parsed in and Unix timestamps are read in.
+By default times are formatted in their own location and
+everything else is read as UTC.
+*/
+func WithTimeZone(location *time.Location) Option {
+	return func(o *options) {
+		o.timeZone = location
+	}
+}
+
+/*
+isTimestamp tells if t holds a time as Seconds and Nanos
+since the Unix epoch, like the proto Timestamp does.
+Such structs are still mapped field by field, unless
+the other side of the mapping is a time.Time.
+*/
+func isTimestamp(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	seconds, hasSeconds := t.FieldByName("Seconds")
+	nanos, hasNanos := t.FieldByName("Nanos")
+	return hasSeconds && hasNanos && isInt(seconds.Type.Kind()) && isInt(nanos.Type.Kind())
+}
+
+/*
+convertTime maps a time.Time onto a string, a Unix timestamp in
+seconds or a timestamp struct, and back, reporting whether it did
+*/
+func (o *options) convertTime(dst, src reflect.Value) (converted bool, err error) {
+	switch {
+	case src.Type() == timeType:
+		return o.setFromTime(dst, src.Interface().(time.Time))
+	case dst.Type() != timeType:
+		return false, nil
+	case src.Kind() == reflect.String:
+		t, err := time.ParseInLocation(o.layout(), src.String(), o.location())
+		if err != nil {
+			return true, err
+		}
+		dst.Set(reflect.ValueOf(t))
+	case isInt(src.Kind()):
+		dst.Set(reflect.ValueOf(time.Unix(src.Int(), 0).In(o.location())))
+	case isUint(src.Kind()):
+		dst.Set(reflect.ValueOf(time.Unix(int64(src.Uint()), 0).In(o.location())))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+/*
+timestampSource finds the timestamp struct a time.Time destination
+reached by names is read from: the source path whose Seconds and
+Nanos fields match those of the destination.
+*/
+func (o *options) timestampSource(names []string, srcDescription map[string]typeDescription) (sourceMatch, bool) {
+	secondsNames := make([]string, len(names))
+	for i, name := range names {
+		secondsNames[i] = appendFieldName(name, "Seconds")
+	}
+	seconds, found := o.findSource(secondsNames, srcDescription)
+	if !found || !strings.HasSuffix(seconds.Path, ".Seconds") {
+		return sourceMatch{}, false
+	}
+	path := strings.TrimSuffix(seconds.Path, ".Seconds")
+	nanos, found := srcDescription[path+".Nanos"]
+	if !found || !isInt(indirectType(seconds.FieldType).Kind()) || !isInt(indirectType(nanos.FieldType).Kind()) {
+		return sourceMatch{}, false
+	}
+
+	match := sourceMatch{Path: path, Rank: seconds.Rank, typeDescription: typeDescription{FieldType: timeType, Order: seconds.Order}}
+	secondsValue, nanosValue := reflect.Indirect(seconds.FieldValue), reflect.Indirect(nanos.FieldValue)
+	if secondsValue.IsValid() && nanosValue.IsValid() {
+		match.FieldValue = reflect.ValueOf(time.Unix(secondsValue.Int(), nanosValue.Int()).In(o.location()))
+	}
+	return match, true
+}
+
+/*
+isTimeOnto tells if a time.Time source is mapped onto the
+timestamp struct dstType as a whole
+*/
+func isTimeOnto(dstType reflect.Type, val sourceMatch, found bool) bool {
+	return found && val.FieldType != nil && indirectType(val.FieldType) == timeType && isTimestamp(indirectType(dstType))
+}
+
+func (o *options) setFromTime(dst reflect.Value, t time.Time) (converted bool, err error) {
+	switch {
+	case dst.Kind() == reflect.String:
+		if o.timeZone != nil {
+			t = t.In(o.timeZone)
+		}
+		dst.SetString(t.Format(o.layout()))
+	case isInt(dst.Kind()) || isUint(dst.Kind()):
+		return true, setFromInt(dst, t.Unix(), o.conversionPolicy)
+	case isTimestamp(dst.Type()):
+		if err := setFromInt(dst.FieldByName("Seconds"), t.Unix(), o.conversionPolicy); err != nil {
+			return true, err
+		}
+		dst.FieldByName("Nanos").SetInt(int64(t.Nanosecond()))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+func (o *options) layout() string {
+	if len(o.timeLayout) == 0 {
+		return time.RFC3339
+	}
+	return o.timeLayout
+}
+
+func (o *options) location() *time.Location {
+	if o.timeZone == nil {
+		return time.UTC
+	}
+	return o.timeZone
+}
@@ -0,0 +1,122 @@
+package animagi_test
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type Timestamp struct {
+	Seconds int64
+	Nanos   int32
+}
+
+type RunStats struct {
+	Seconds int64
+	Nanos   int32
+	Label   string
+}
+
+type RunStatsDTO struct {
+	Seconds int64
+	Nanos   int32
+	Label   string
+	Extra   string
+}
+
+type Event struct {
+	Name     string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Created  time.Time
+}
+
+type EventMessage struct {
+	Name     string
+	StartsAt string
+	EndsAt   int64
+	Created  *Timestamp
+}
+
+var _ = Describe("Time conversions", func() {
+
+	starts := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	created := time.Date(2020, 1, 2, 3, 4, 5, 600, time.UTC)
+	event := Event{"launch", starts, starts.Add(time.Hour), created}
+
+	It("Should map times onto strings, Unix timestamps and timestamp structs", func() {
+		var message EventMessage
+		err := animagi.Transform(event, &message)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(message.StartsAt).To(Equal("2021-03-04T05:06:07Z"))
+		Expect(message.EndsAt).To(Equal(starts.Add(time.Hour).Unix()))
+		Expect(*message.Created).To(Equal(Timestamp{created.Unix(), 600}))
+	})
+
+	It("Should map them back into times", func() {
+		var message EventMessage
+		Expect(animagi.Transform(event, &message)).To(Succeed())
+
+		var back Event
+		err := animagi.Transform(message, &back)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(back).To(Equal(event))
+	})
+
+	It("Should use the layout and zone given", func() {
+		zone := time.FixedZone("UTC+2", 2*60*60)
+		opts := []animagi.Option{animagi.WithTimeLayout("2006-01-02 15:04"), animagi.WithTimeZone(zone)}
+
+		var message EventMessage
+		Expect(animagi.Transform(event, &message, opts...)).To(Succeed())
+		Expect(message.StartsAt).To(Equal("2021-03-04 07:06"))
+
+		var back Event
+		Expect(animagi.Transform(message, &back, opts...)).To(Succeed())
+		Expect(back.StartsAt.Equal(starts.Truncate(time.Minute))).To(BeTrue())
+		Expect(back.StartsAt.Location()).To(Equal(zone))
+	})
+
+	It("Should report strings that do not parse", func() {
+		var back Event
+		err := animagi.Transform(EventMessage{StartsAt: "tomorrow"}, &back)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("StartsAt"))
+	})
+
+	It("Should never decompose times", func() {
+		var dst struct {
+			StartsAt struct{ Wall uint64 }
+		}
+		err := animagi.Transform(event, &dst, animagi.WithStrict())
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)[0].Path).To(Equal("StartsAt.Wall"))
+	})
+
+	It("Should map structs with Seconds and Nanos field by field", func() {
+		src := struct{ Run RunStats }{RunStats{5, 6, "warm up"}}
+		var dst struct{ Run RunStatsDTO }
+		err := animagi.Transform(src, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dst.Run).To(Equal(RunStatsDTO{5, 6, "warm up", ""}))
+
+		var ptr struct{ Run *RunStatsDTO }
+		Expect(animagi.Transform(src, &ptr)).To(Succeed())
+		Expect(*ptr.Run).To(Equal(RunStatsDTO{5, 6, "warm up", ""}))
+	})
+
+	It("Should report timestamp conversions in CanTransform", func() {
+		report := animagi.CanTransform(reflect.TypeOf(Event{}), reflect.TypeOf(EventMessage{}))
+		Expect(report.Unmapped).To(BeEmpty())
+		Expect(report.Unused).To(BeEmpty())
+
+		report = animagi.CanTransform(reflect.TypeOf(EventMessage{}), reflect.TypeOf(Event{}))
+		Expect(report.Unmapped).To(BeEmpty())
+		Expect(report.Unused).To(BeEmpty())
+		Expect(report.DstCoverage).To(BeNumerically("==", 100))
+	})
+})
@@ -0,0 +1,154 @@
+package animagi
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*
+DefaultDictionary returns the common programming abbreviations,
+each with the words it stands for, to use as the Dictionary of
+SimilarityOptions or to add synonyms of a domain to
+*/
+func DefaultDictionary() map[string]string {
+	return map[string]string{
+		"acct":     "account",
+		"addr":     "address",
+		"amt":      "amount",
+		"avg":      "average",
+		"cfg":      "configuration",
+		"cnt":      "count",
+		"conf":     "configuration",
+		"config":   "configuration",
+		"desc":     "description",
+		"dest":     "destination",
+		"dept":     "department",
+		"dob":      "date of birth",
+		"dst":      "destination",
+		"dt":       "date",
+		"err":      "error",
+		"fname":    "first name",
+		"id":       "identifier",
+		"idx":      "index",
+		"img":      "image",
+		"info":     "information",
+		"lat":      "latitude",
+		"len":      "length",
+		"lname":    "last name",
+		"lng":      "longitude",
+		"lon":      "longitude",
+		"max":      "maximum",
+		"mgr":      "manager",
+		"min":      "minimum",
+		"msg":      "message",
+		"nbr":      "number",
+		"num":      "number",
+		"org":      "organization",
+		"passwd":   "password",
+		"pos":      "position",
+		"postcode": "postal code",
+		"pwd":      "password",
+		"qty":      "quantity",
+		"ref":      "reference",
+		"req":      "request",
+		"resp":     "response",
+		"src":      "source",
+		"str":      "string",
+		"tel":      "telephone",
+		"ts":       "timestamp",
+		"usr":      "user",
+		"val":      "value",
+		"zip":      "postal code",
+		"zipcode":  "postal code",
+	}
+}
+
+/*
+wordRank ranks two names as sequences of words: changing a word costs
+its letter rank, a missing word MissingLetter for each of its letters
+*/
+func (similarity SimilarityOptions) wordRank(str1, str2 string) uint {
+	words1 := similarity.words(str1)
+	words2 := similarity.words(str2)
+
+	previous := make([]uint, len(words2)+1)
+	current := make([]uint, len(words2)+1)
+	for j := 1; j <= len(words2); j++ {
+		previous[j] = addRank(previous[j-1], similarity.letterRank("", words2[j-1]))
+	}
+	for i := 1; i <= len(words1); i++ {
+		current[0] = addRank(previous[0], similarity.letterRank(words1[i-1], ""))
+		for j := 1; j <= len(words2); j++ {
+			current[j] = addRank(previous[j-1], similarity.letterRank(words1[i-1], words2[j-1]))
+			if missing := addRank(previous[j], similarity.letterRank(words1[i-1], "")); missing < current[j] {
+				current[j] = missing
+			}
+			if missing := addRank(current[j-1], similarity.letterRank("", words2[j-1])); missing < current[j] {
+				current[j] = missing
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(words2)]
+}
+
+/*
+words splits name into lower case words and replaces
+the ones found in the Dictionary
+*/
+func (similarity SimilarityOptions) words(name string) (words []string) {
+	for _, token := range tokenize(name) {
+		if replacement, found := similarity.Dictionary[token]; found {
+			words = append(words, strings.Fields(replacement)...)
+		} else {
+			words = append(words, token)
+		}
+	}
+	return words
+}
+
+/*
+tokenize splits name into lower case words at separators,
+case changes and digits, as HTTPServer2_name is http server 2 name
+*/
+func tokenize(name string) (tokens []string) {
+	runes := []rune(name)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				tokens = append(tokens, strings.ToLower(string(runes[start:i])))
+			}
+			start = -1
+			continue
+		}
+		if start >= 0 && startsWord(runes, i) {
+			tokens = append(tokens, strings.ToLower(string(runes[start:i])))
+			start = i
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, strings.ToLower(string(runes[start:])))
+	}
+	return tokens
+}
+
+/*
+startsWord tells if the rune at i begins a new word: an upper case
+letter after a lower case one or ending an acronym, or a change
+between letters and digits
+*/
+func startsWord(runes []rune, i int) bool {
+	previous, r := runes[i-1], runes[i]
+	switch {
+	case unicode.IsDigit(previous) != unicode.IsDigit(r):
+		return true
+	case unicode.IsUpper(r) && unicode.IsLower(previous):
+		return true
+	default:
+		return unicode.IsUpper(r) && unicode.IsUpper(previous) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+	}
+}
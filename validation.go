@@ -0,0 +1,54 @@
+package animagi
+
+import (
+	"reflect"
+)
+
+/*
+Validator is implemented by destination types that can check
+themselves once Transform has filled them
+*/
+type Validator interface {
+	Validate() error
+}
+
+/*
+ValidationMode decides if and how destinations are validated
+*/
+type ValidationMode int
+
+const (
+	// ValidateOff never calls Validate
+	ValidateOff ValidationMode = iota
+	// ValidateAll calls Validate on every filled struct and reports every failure
+	ValidateAll
+	// ValidateFailFast stops validating after the first failure
+	ValidateFailFast
+)
+
+/*
+WithValidation makes Transform call Validate on the destination and
+on every nested struct it filled that implements Validator.
+Nested structs are validated before the structs holding them and
+failures are reported as FieldErrors on the path of the struct.
+*/
+func WithValidation(mode ValidationMode) Option {
+	return func(o *options) {
+		o.validation = mode
+	}
+}
+
+func (o *options) validate(path string, value reflect.Value, errs *FieldErrors) {
+	if o.validation == ValidateOff || (o.validation == ValidateFailFast && o.validationFailed) {
+		return
+	}
+	if value.CanAddr() {
+		value = value.Addr()
+	}
+	if validator, ok := valueInterface(value).(Validator); ok {
+		if err := validator.Validate(); err != nil {
+			o.validationFailed = true
+			errs.add(path, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package animagi_test
+
+import (
+	"errors"
+
+	"github.com/barreeyentos/animagi"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var validated []string
+
+type ValidatedAddress struct {
+	Street string
+	City   string
+}
+
+func (a *ValidatedAddress) Validate() error {
+	validated = append(validated, "address")
+	if len(a.City) == 0 {
+		return errors.New("city is required")
+	}
+	return nil
+}
+
+type ValidatedUser struct {
+	Name    string
+	Address ValidatedAddress
+	Billing *ValidatedAddress
+}
+
+func (u ValidatedUser) Validate() error {
+	validated = append(validated, "user")
+	if len(u.Name) == 0 {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+var _ = Describe("Validation", func() {
+
+	BeforeEach(func() {
+		validated = nil
+	})
+
+	It("Should not validate by default", func() {
+		var dst ValidatedUser
+		err := animagi.Transform(struct{ Nickname string }{"jj"}, &dst)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validated).To(BeEmpty())
+	})
+
+	It("Should validate filled structs bottom up", func() {
+		src := struct {
+			Name    string
+			Address Address
+			Billing Address
+		}{"Jane", Address{"Main St", "Springfield"}, Address{"Side St", "Shelbyville"}}
+
+		var dst ValidatedUser
+		err := animagi.Transform(src, &dst, animagi.WithValidation(animagi.ValidateAll))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validated).To(Equal([]string{"address", "address", "user"}))
+	})
+
+	It("Should only validate nested structs that were filled", func() {
+		src := struct{ Name string }{"Jane"}
+		var dst ValidatedUser
+		err := animagi.Transform(src, &dst, animagi.WithValidation(animagi.ValidateAll))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validated).To(Equal([]string{"user"}))
+	})
+
+	It("Should aggregate failures with their paths", func() {
+		src := struct {
+			Address Address
+			Billing Address
+		}{Address{Street: "Main St"}, Address{Street: "Side St"}}
+
+		var dst ValidatedUser
+		err := animagi.Transform(src, &dst, animagi.WithValidation(animagi.ValidateAll))
+		Expect(err).To(HaveOccurred())
+		errs := err.(animagi.FieldErrors)
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[0].Path).To(Equal("Address"))
+		Expect(errs[1].Path).To(Equal("Billing"))
+		Expect(errs[2].Path).To(BeEmpty())
+		Expect(errs[2].Err).To(MatchError("name is required"))
+	})
+
+	It("Should short-circuit after the first failure", func() {
+		src := struct {
+			Address Address
+			Billing Address
+		}{Address{Street: "Main St"}, Address{Street: "Side St"}}
+
+		var dst ValidatedUser
+		err := animagi.Transform(src, &dst, animagi.WithValidation(animagi.ValidateFailFast))
+		Expect(err).To(HaveOccurred())
+		Expect(err.(animagi.FieldErrors)).To(HaveLen(1))
+		Expect(validated).To(Equal([]string{"address"}))
+		Expect(dst.Billing.Street).To(Equal("Side St"))
+	})
+})